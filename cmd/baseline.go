@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/baseline"
+	"github.com/gitKashish/golog/internal/core/patterns"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Baseline flags
+	baselinePath       string
+	baselineOutputPath string
+	baselineThreshold  float64
+	baselineMinCount   int
+	baselineFormat     string
+)
+
+// baselineCmd represents the baseline command
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Manage baselines of known log patterns",
+	Long: `Manage baselines of known log patterns.
+
+A baseline holds the message patterns of a log file and how often they occur,
+in a compact file that golog new compares other log files to.`,
+}
+
+// baselineSaveCmd represents the baseline save command
+var baselineSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save the message patterns of a log file as a baseline",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := mineBaseline(inputFilePath, patternField)
+		if err != nil {
+			return err
+		}
+
+		if err := baseline.Save(b, baselineOutputPath); err != nil {
+			logger.Error("Error saving baseline: %v", err)
+			return err
+		}
+		logger.Info("Saved %d patterns from %d messages to %s", len(b.Patterns), b.Messages, baselineOutputPath)
+		return nil
+	},
+}
+
+// newCmd represents the new command
+var newCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Report log patterns that are new or changed compared to a baseline",
+	Long: `Report log patterns that are new or changed compared to a baseline.
+
+The baseline is either a file saved with golog baseline save or a log file,
+whose patterns are mined first. Patterns only found in the input are reported
+as new, and patterns whose share of messages grew or shrank by at least the
+--threshold factor as increased or decreased. Baseline patterns missing from
+the input are reported as gone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if baselineFormat != "table" && baselineFormat != "json" {
+			return fmt.Errorf("invalid format `%s`, expected table or json", baselineFormat)
+		}
+
+		var base *baseline.Baseline
+		var err error
+		if baseline.IsFile(baselinePath) {
+			if base, err = baseline.Load(baselinePath); err != nil {
+				logger.Error("Error loading baseline: %v", err)
+				return err
+			}
+			// Read messages from the same field as the baseline unless told otherwise
+			if !cmd.Flags().Changed("field") {
+				patternField = base.Field
+			}
+		} else if base, err = mineBaseline(baselinePath, patternField); err != nil {
+			return err
+		}
+
+		comparer := baseline.NewComparer(base, patterns.Options{
+			Similarity:  patternSimilarity,
+			MaxClusters: patternMaxClusters,
+		})
+		if _, err := mineMessages(inputFilePath, patternField, patternTimeField, comparer.Add); err != nil {
+			return err
+		}
+
+		changes := comparer.Changes(baselineThreshold, baselineMinCount)
+		if baselineFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(changes)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHANGE\tBASELINE\tCOUNT\tRATE\tPATTERN\tEXAMPLE")
+		for _, change := range changes {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", change.Kind, change.BaseCount, change.Count,
+				formatRate(change), change.Pattern, change.Example)
+		}
+		return w.Flush()
+	},
+}
+
+// mineBaseline creates a baseline from the messages of a log file
+func mineBaseline(path, field string) (*baseline.Baseline, error) {
+	miner := patterns.NewMiner(patterns.Options{
+		Similarity:  patternSimilarity,
+		MaxClusters: patternMaxClusters,
+	})
+	add := func(message string, timestamp time.Time, line string) {
+		miner.Add(message, timestamp, line)
+	}
+	messages, err := mineMessages(path, field, patternTimeField, add)
+	if err != nil {
+		return nil, err
+	}
+	return baseline.New(miner, field, messages), nil
+}
+
+// formatRate describes the change of a pattern's share of messages
+func formatRate(change baseline.Change) string {
+	switch change.Kind {
+	case baseline.ChangeNew, baseline.ChangeGone:
+		return "-"
+	default:
+		return fmt.Sprintf("x%.1f", change.Rate/change.BaseRate)
+	}
+}
+
+// addMiningFlags adds the flags selecting the messages to mine patterns from
+func addMiningFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&patternField, "field", "", "Field holding the message, whole lines if empty")
+	cmd.Flags().StringVar(&patternTimeField, "time-field", "", "Field holding the time of records (default is the first timestamp field)")
+	cmd.Flags().Float64Var(&patternSimilarity, "similarity", patterns.DefaultSimilarity, "Share of tokens a message must share with a pattern to join it")
+	cmd.Flags().IntVar(&patternMaxClusters, "max-clusters", patterns.DefaultMaxClusters, "Maximum number of patterns kept in memory")
+	addTemplateFlags(cmd)
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineSaveCmd)
+	rootCmd.AddCommand(newCmd)
+
+	baselineSaveCmd.Flags().StringVarP(&inputFilePath, "input", "i", "", "Path to input file (required)")
+	baselineSaveCmd.Flags().StringVarP(&baselineOutputPath, "output", "o", "baseline.golog", "Path to write the baseline to")
+	baselineSaveCmd.MarkFlagFilename("input")
+	baselineSaveCmd.MarkFlagRequired("input")
+	addMiningFlags(baselineSaveCmd)
+
+	newCmd.Flags().StringVarP(&inputFilePath, "input", "i", "", "Path to input file (required)")
+	newCmd.Flags().StringVar(&baselinePath, "baseline", "", "Path to a baseline file or a log file to compare to (required)")
+	newCmd.Flags().Float64Var(&baselineThreshold, "threshold", baseline.DefaultThreshold, "Factor by which the rate of a pattern must change to be reported")
+	newCmd.Flags().IntVar(&baselineMinCount, "min-count", 1, "Ignore patterns seen fewer times than this")
+	newCmd.Flags().StringVar(&baselineFormat, "format", "table", "Output format: table or json")
+	newCmd.MarkFlagFilename("input")
+	newCmd.MarkFlagFilename("baseline")
+	newCmd.MarkFlagRequired("input")
+	newCmd.MarkFlagRequired("baseline")
+	addMiningFlags(newCmd)
+}
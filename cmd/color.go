@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/gitKashish/golog/internal/config"
+	"github.com/gitKashish/golog/internal/core/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Color flags
+	colorMode string
+)
+
+// addColorFlag adds the flag deciding when output is colored to a command
+func addColorFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&colorMode, "color", color.ModeAuto, "When to color output: auto, always or never")
+	cmd.Flags().Lookup("color").NoOptDefVal = color.ModeAlways
+}
+
+// newPainter returns the painter coloring output written to stdout, nil if
+// output isn't colored
+func newPainter(cmd *cobra.Command) (*color.Painter, error) {
+	if cmd.Flags().Changed("color") {
+		if err := cfg.Set("color.mode", colorMode, config.FlagSource("color")); err != nil {
+			return nil, err
+		}
+	}
+
+	enabled, err := color.Enabled(cfg.Color.Mode, os.Stdout)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	return color.NewPainter(cfg.Color.Theme)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect golog configuration",
+	Long: `Inspect the configuration golog runs with.
+
+Configuration is read from golog.yaml, looked up in the .golog directory of the
+working directory or its parents and then in $XDG_CONFIG_HOME/golog. Values are
+applied in order of precedence: defaults, config file, GOLOG_* environment
+variables and finally command line flags.`,
+}
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration and the source of each value",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Path != "" {
+			fmt.Printf("Config file: %s\n\n", cfg.Path)
+		} else {
+			fmt.Printf("Config file: none\n\n")
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+		for _, value := range cfg.Values() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", value.Key, value.Value, value.Source)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+}
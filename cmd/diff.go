@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gitKashish/golog/internal/core/diff"
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Diff flags
+	diffIgnoreFields []string
+	diffContext      int
+	diffExitCode     bool
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <a.log> <b.log>",
+	Short: "Compare the records of two log files",
+	Long: `Compare the records of two log files, like the logs of two test runs.
+
+Records are aligned by their field values, leaving out the fields given with
+--ignore-fields such as timestamps or instance names, and the differences are
+shown in unified diff format. Records with the same fields and mostly the same
+values are shown as changed, followed by the fields that differ.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := newParser()
+		if err != nil {
+			return err
+		}
+
+		a, err := readRecords(p, args[0])
+		if err != nil {
+			return err
+		}
+		b, err := readRecords(p, args[1])
+		if err != nil {
+			return err
+		}
+
+		edits := diff.Records(a, b, diffIgnoreFields)
+		fmt.Print(diff.UnifiedRecords(edits, args[0], args[1], diffContext))
+
+		counts := map[byte]int{}
+		for _, edit := range edits {
+			counts[edit.Kind]++
+		}
+		logger.Info("%d changed, %d removed, %d added, %d unchanged records",
+			counts[diff.Changed], counts[diff.Removed], counts[diff.Added], counts[diff.Equal])
+
+		if diffExitCode && len(edits) > counts[diff.Equal] {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// readRecords parses all records of a file
+func readRecords(p *parser.TemplateParser, path string) ([]*models.Record, error) {
+	lines, err := newFileUtil().ReadLines(path)
+	if err != nil {
+		logger.Error("Error reading file: %v", err)
+		return nil, err
+	}
+
+	records, err := p.ParseRecords(lines)
+	if err != nil {
+		logger.Error("Error parsing %s: %v", path, err)
+		return nil, err
+	}
+	return records, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringSliceVar(&diffIgnoreFields, "ignore-fields", nil, "Fields left out when comparing records, like time,instance")
+	diffCmd.Flags().IntVarP(&diffContext, "context", "U", diff.DefaultContext, "Number of unchanged records shown around differences")
+	diffCmd.Flags().BoolVar(&diffExitCode, "exit-code", false, "Exit with status 1 if the files differ")
+
+	addTemplateFlags(diffCmd)
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/grouping"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Error grouping flags
+	errorsBy           []string
+	errorsLevelField   string
+	errorsMessageField string
+	errorsAffected     string
+	errorsTimeField    string
+	errorsSince        string
+	errorsFrames       int
+	errorsFormat       string
+)
+
+// errorGroupSummary is a group as written in JSON output
+type errorGroupSummary struct {
+	Fingerprint string                `json:"fingerprint"`
+	Count       int                   `json:"count"`
+	First       *time.Time            `json:"first,omitempty"`
+	Last        *time.Time            `json:"last,omitempty"`
+	Affected    []grouping.ValueCount `json:"affected,omitempty"`
+	Example     string                `json:"example"`
+	Stack       []string              `json:"stack,omitempty"`
+}
+
+// errorsCmd represents the errors command
+var errorsCmd = &cobra.Command{
+	Use:   "errors",
+	Short: "Group error records by fingerprint",
+	Long: `Group error records by fingerprint and report when each group was first and last seen.
+
+Lines that don't match the template are taken as the continuation of the
+previous record, like the lines of a stack trace. Records are errors if they
+have an error level or a stack trace, and are grouped by their top stack
+frames or else by their message with numbers and IP addresses masked.
+
+With --by, records holding all the given fields are grouped by their values
+instead, like --by details.ERROR.code,api. Records with a level below error
+are still left out, records without a level are included.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if errorsFormat != "table" && errorsFormat != "json" {
+			return fmt.Errorf("invalid format `%s`, expected table or json", errorsFormat)
+		}
+
+		options := grouping.Options{
+			By:            errorsBy,
+			LevelField:    errorsLevelField,
+			MessageField:  errorsMessageField,
+			AffectedField: errorsAffected,
+			TimeField:     errorsTimeField,
+			Frames:        errorsFrames,
+		}
+		if errorsSince != "" {
+			since, err := grouping.ParseSince(errorsSince, time.Now())
+			if err != nil {
+				return err
+			}
+			options.Since = since
+		}
+
+		p, err := newParser()
+		if err != nil {
+			return err
+		}
+
+		options.Continue = p.Continue
+		grouper := grouping.NewGrouper(options)
+		err = newFileUtil().EachLine(inputFilePath, func(line string) error {
+			grouper.Add(p.ParseRecord(line))
+			return nil
+		})
+		if err != nil {
+			logger.Error("Error reading file: %v", err)
+			return err
+		}
+		grouper.Flush()
+		if grouper.Untimed() > 0 {
+			logger.Warn("Left out %d error records without a time from --since, pass --time-field to name the field holding it", grouper.Untimed())
+		}
+
+		if errorsFormat == "json" {
+			return printErrorsJSON(grouper.Groups())
+		}
+		return printErrorsTable(grouper.Groups())
+	},
+}
+
+// printErrorsTable prints groups as an aligned table
+func printErrorsTable(groups []*grouping.Group) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNT\tFIRST SEEN\tLAST SEEN\tAFFECTED\tFINGERPRINT\tEXAMPLE")
+	for _, group := range groups {
+		affected := []string{}
+		for _, value := range group.TopAffected(3) {
+			affected = append(affected, fmt.Sprintf("%s (%d)", value.Value, value.Count))
+		}
+		if len(group.Affected) > 3 {
+			affected = append(affected, fmt.Sprintf("+%d more", len(group.Affected)-3))
+		}
+		if len(affected) == 0 {
+			affected = append(affected, "-")
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", group.Count, formatTime(group.First), formatTime(group.Last),
+			strings.Join(affected, ", "), group.Fingerprint, group.Example.Raw)
+	}
+	return w.Flush()
+}
+
+// printErrorsJSON prints groups as a JSON array
+func printErrorsJSON(groups []*grouping.Group) error {
+	summaries := make([]errorGroupSummary, len(groups))
+	for i, group := range groups {
+		summaries[i] = errorGroupSummary{
+			Fingerprint: group.Fingerprint,
+			Count:       group.Count,
+			Affected:    group.TopAffected(0),
+			Example:     group.Example.Raw,
+			Stack:       group.Stack,
+		}
+		if !group.First.IsZero() {
+			summaries[i].First, summaries[i].Last = &group.First, &group.Last
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summaries)
+}
+
+func init() {
+	rootCmd.AddCommand(errorsCmd)
+
+	errorsCmd.Flags().StringVarP(&inputFilePath, "input", "i", "", "Path to input file (required)")
+	errorsCmd.Flags().StringSliceVar(&errorsBy, "by", nil, "Fields making up the fingerprint, like details.ERROR.code,api")
+	errorsCmd.Flags().StringVar(&errorsLevelField, "level-field", "", "Field holding the level of records (default is level or severity)")
+	errorsCmd.Flags().StringVar(&errorsMessageField, "message-field", "message", "Field holding the message, used to group records without a stack trace")
+	errorsCmd.Flags().StringVar(&errorsAffected, "affected", "", "Field whose distinct values are counted for each group")
+	errorsCmd.Flags().StringVar(&errorsTimeField, "time-field", "", "Field holding the time of records (default is the first timestamp field)")
+	errorsCmd.Flags().StringVar(&errorsSince, "since", "", "Only include records since a duration ago like 24h or 7d, or since a timestamp")
+	errorsCmd.Flags().IntVar(&errorsFrames, "frames", grouping.DefaultFrames, "Number of stack frames making up a fingerprint")
+	errorsCmd.Flags().StringVar(&errorsFormat, "format", "table", "Output format: table or json")
+	errorsCmd.MarkFlagFilename("input")
+	errorsCmd.MarkFlagRequired("input")
+
+	addTemplateFlags(errorsCmd)
+}
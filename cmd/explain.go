@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Explain flags
+	explainFormat string
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain <line>",
+	Short: "Show how a line is matched against the template",
+	Long: `Show how a line is matched against the template.
+
+Each segment of the source template is listed with the part of the line it
+matched, followed by the raw and formatted value of each field. If the line
+doesn't match, explain shows how far matching got and the segment of the
+template where it stopped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if explainFormat != "table" && explainFormat != "json" {
+			return fmt.Errorf("invalid format `%s`, expected table or json", explainFormat)
+		}
+
+		p, err := newParser()
+		if err != nil {
+			return err
+		}
+
+		explanation := p.Explain(args[0])
+		if explainFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(explanation)
+		}
+		return printExplanation(explanation)
+	},
+}
+
+// printExplanation prints the segments, fields and output of an explanation
+func printExplanation(explanation *parser.Explanation) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(explanation.Segments) > 0 {
+		fmt.Fprintln(w, "SEGMENT\tBYTES\tMATCHED")
+		for _, segment := range explanation.Segments {
+			if segment.Start < 0 {
+				fmt.Fprintf(w, "%q\t-\t-\n", segment.Text)
+				continue
+			}
+			fmt.Fprintf(w, "%q\t%d-%d\t%q\n", segment.Text, segment.Start, segment.End,
+				explanation.Line[segment.Start:segment.End])
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(explanation.Fields) > 0 {
+		fmt.Fprintln(w, "FIELD\tTYPE\tVALUE\tFORMATTED")
+		for _, field := range explanation.Fields {
+			fmt.Fprintf(w, "%s\t%s\t%q\t%q\n", field.Name, field.Type, field.Value, field.Formatted)
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if explanation.Matched {
+		fmt.Printf("Output:\n%s\n", explanation.Output)
+		return nil
+	}
+
+	fmt.Println("No match")
+	if len(explanation.Segments) == 0 {
+		return nil
+	}
+
+	// Point at the end of the matched prefix
+	column := utf8.RuneCountInString(explanation.Line[:explanation.Prefix])
+	fmt.Printf("  %s\n  %s^ %s\n", explanation.Line, strings.Repeat(" ", column), stopReason(explanation))
+	return nil
+}
+
+// stopReason describes why matching stopped at the segment it did
+func stopReason(explanation *parser.Explanation) string {
+	if explanation.Stopped >= len(explanation.Segments) {
+		return "the template ends here, but the line goes on"
+	}
+
+	segment := explanation.Segments[explanation.Stopped]
+	if segment.Field != "" && segment.Start >= 0 {
+		return fmt.Sprintf("field %s matches, but its type rejects the value", segment.Text)
+	}
+	if segment.Field != "" {
+		return fmt.Sprintf("field %s doesn't match here", segment.Text)
+	}
+	if explanation.Stopped > 0 {
+		// Fields without a width could have ended anywhere after this point
+		if previous := explanation.Segments[explanation.Stopped-1]; previous.Field != "" && previous.Width == 0 {
+			return fmt.Sprintf("expected %q after field %s, not found in the rest of the line", segment.Text, previous.Text)
+		}
+	}
+	return fmt.Sprintf("expected %q", segment.Text)
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringVar(&explainFormat, "format", "table", "Output format: table or json")
+	addTemplateFlags(explainCmd)
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gitKashish/golog/internal/core/infer"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// Number of sample lines to infer a template from
+	inferLines int
+	// Path to write the inferred template to
+	inferOutputPath string
+)
+
+// inferCmd represents the infer command
+var inferCmd = &cobra.Command{
+	Use:   "infer",
+	Short: "Generate a template from sample log lines",
+	Long: `Generate a template.yaml from sample log lines.
+
+The sample lines are compared to find the parts shared by every line, which
+become the skeleton of the source template, and the parts that vary, which
+become fields. Field types are guessed from their values. The generated
+template is a starting point meant to be reviewed and renamed by hand.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fileUtil := newFileUtil()
+
+		lines, err := fileUtil.ReadLines(inputFilePath)
+		if err != nil {
+			logger.Error("Error reading file: %v", err)
+			return err
+		}
+		if inferLines > 0 && len(lines) > inferLines {
+			lines = lines[:inferLines]
+		}
+
+		result, err := infer.Infer(lines)
+		if err != nil {
+			logger.Error("Error inferring template: %v", err)
+			return err
+		}
+
+		data, err := yaml.Marshal(map[string]string{
+			"sourceTemplate": result.Literals.Source,
+			"targetTemplate": result.Literals.Target,
+		})
+		if err != nil {
+			return err
+		}
+		summary := fmt.Sprintf("Inferred from %d lines, matches %d (%.1f%%)",
+			result.Lines, result.Matched, result.MatchRate()*100)
+		content := "# " + summary + "\n" + string(data)
+
+		if inferOutputPath == "" {
+			fmt.Print(content)
+			return nil
+		}
+
+		if err := os.WriteFile(inferOutputPath, []byte(content), 0o644); err != nil {
+			logger.Error("Error writing file: %v", err)
+			return err
+		}
+		logger.Info("%s, written to %s", summary, inferOutputPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inferCmd)
+
+	inferCmd.Flags().StringVarP(&inputFilePath, "input", "i", "", "Path to file with sample lines (required)")
+	inferCmd.Flags().StringVarP(&inferOutputPath, "output", "o", "", "Path to write the template to, prints it if empty")
+	inferCmd.Flags().IntVarP(&inferLines, "lines", "n", 1000, "Number of sample lines to read, 0 for all")
+	inferCmd.MarkFlagFilename("input")
+	inferCmd.MarkFlagFilename("output")
+	inferCmd.MarkFlagRequired("input")
+}
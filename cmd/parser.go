@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gitKashish/golog/internal/core/formatter"
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/internal/core/presets"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Inline template flags
+	sourceTemplate string
+	targetTemplate string
+	presetName     string
+	inputFormat    string
+	strictTypes    bool
+
+	// Unmatched line flags
+	onMismatch      string
+	rejectsFilePath string
+
+	// Parallel parsing flags
+	workers int
+
+	// Filter flags
+	minLevel   string
+	conditions []string
+)
+
+// addTemplateFlags adds the flags selecting the template to a command
+func addTemplateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sourceTemplate, "source", "", "Inline source template, overrides the template file")
+	cmd.Flags().StringVar(&targetTemplate, "target", "", "Inline target template, overrides the template file")
+	cmd.Flags().StringVar(&presetName, "preset", "", "Name of a built-in template to use, see golog presets list")
+	cmd.Flags().StringVar(&inputFormat, "input-format", "", "Format of input entries: template, json, logfmt, csv, tsv or delimited")
+	cmd.Flags().BoolVar(&strictTypes, "strict-types", false, "Treat entries with values their field type rejects as unmatched")
+}
+
+// addMismatchFlags adds the flags handling lines that don't match the template to a command
+func addMismatchFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&onMismatch, "on-mismatch", "", "What to do with unmatched lines: passthrough, drop, append-to-previous, mark or reject")
+	cmd.Flags().StringVar(&rejectsFilePath, "rejects", "", "Path to the file receiving rejected lines with their line numbers")
+	cmd.MarkFlagFilename("rejects")
+}
+
+// addWorkersFlag adds the flag setting the number of goroutines parsing lines to a command
+func addWorkersFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&workers, "workers", 1, "Number of workers parsing chunks of the input concurrently")
+}
+
+// addLevelFlag adds the flag filtering records by level to a command
+func addLevelFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&minLevel, "min-level", "", "Only output records at or above this level, like warn or error")
+}
+
+// addWhereFlag adds the flag filtering records by the values of their fields to a command
+func addWhereFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&conditions, "where", nil, "Only output records whose field satisfies a condition like status>=500 or client=10.0.0.0/8, may be repeated")
+}
+
+// whereConditions parses the conditions set with --where
+func whereConditions() ([]*formatter.Condition, error) {
+	parsed := make([]*formatter.Condition, len(conditions))
+	for i, expression := range conditions {
+		condition, err := formatter.ParseCondition(expression)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = condition
+	}
+	return parsed, nil
+}
+
+// minSeverity returns the severity set with --min-level, unknown if none was set
+func minSeverity() (models.Severity, error) {
+	if minLevel == "" {
+		return models.SeverityUnknown, nil
+	}
+	severity, ok := models.ParseSeverity(minLevel)
+	if !ok {
+		return severity, fmt.Errorf("invalid level `%s`, expected trace, debug, info, warn, error or fatal", minLevel)
+	}
+	return severity, nil
+}
+
+// filterSeverity logs the number of records of each level and keeps the
+// records at or above a minimum severity
+func filterSeverity(records []*models.Record, minimum models.Severity) []*models.Record {
+	if counts := formatter.CountSeverities(records); len(counts) > 0 {
+		logger.Info("Levels: %s", counts)
+	}
+	if minimum == models.SeverityUnknown {
+		return records
+	}
+	return formatter.FilterSeverity(records, minimum)
+}
+
+// templateLiterals returns the template selected by the command line flags.
+// A preset or the template file provides the base template, and inline
+// templates override its parts.
+func templateLiterals() (models.TemplateLiterals, error) {
+	literals := models.TemplateLiterals{}
+
+	switch {
+	case presetName != "":
+		preset, ok := presets.Get(presetName)
+		if !ok {
+			return literals, fmt.Errorf("unknown preset `%s`", presetName)
+		}
+		literals = preset.Literals
+	case targetTemplate == "" || (sourceTemplate == "" && !models.IsSourceless(inputFormat)):
+		var err error
+		literals, err = parser.LoadLiterals(cfg.Template.TemplatePath)
+		if err != nil {
+			return literals, err
+		}
+	}
+
+	if inputFormat != "" {
+		literals.Format = inputFormat
+	}
+	if onMismatch != "" {
+		literals.OnMismatch = onMismatch
+	}
+	if strictTypes {
+		literals.StrictTypes = true
+	}
+	if models.IsSourceless(literals.Format) && sourceTemplate == "" {
+		literals.Source = ""
+	}
+	if sourceTemplate != "" {
+		literals.Source = sourceTemplate
+	}
+	if targetTemplate != "" {
+		literals.Target = targetTemplate
+	}
+	return literals, nil
+}
+
+// templateName describes the template selected by the command line flags
+func templateName() string {
+	switch {
+	case presetName != "":
+		return "preset " + presetName
+	case sourceTemplate != "" || targetTemplate != "":
+		return "inline"
+	}
+	return cfg.Template.TemplatePath
+}
+
+// newParser creates a parser for the template selected by the command line flags
+func newParser() (*parser.TemplateParser, error) {
+	literals, err := templateLiterals()
+	if err != nil {
+		logger.Error("Error loading template: %v", err)
+		return nil, err
+	}
+
+	p := parser.NewTemplateParser()
+	p.SetVendorPaths(cfg.Stacktrace.VendorPaths)
+	if err := p.SetLiterals(literals); err != nil {
+		// Point at the offending part of the template
+		var templateErr *parser.TemplateError
+		if errors.As(err, &templateErr) {
+			fmt.Fprintln(os.Stderr, templateErr.Caret())
+		}
+		logger.Error("Error loading template: %v", err)
+		return nil, err
+	}
+	return p, nil
+}
+
+// handleMismatches applies the template's policy for unmatched records and logs
+// the match rate, so that a change of the log format doesn't go unnoticed
+func handleMismatches(p *parser.TemplateParser, records []*models.Record) ([]*models.Record, error) {
+	matched := 0
+	for _, record := range records {
+		if record.Matched {
+			matched++
+		}
+	}
+	if len(records) > 0 {
+		logger.Info("Matched %d of %d records (%.1f%%)", matched, len(records), 100*float64(matched)/float64(len(records)))
+	}
+
+	policy := formatter.NewMismatchPolicy(p.Literals())
+	policy.Continue = p.Continue
+	if policy.Action != models.MismatchReject {
+		return policy.Apply(records, nil)
+	}
+
+	if rejectsFilePath == "" {
+		err := errors.New("onMismatch reject requires --rejects")
+		logger.Error("Error handling unmatched lines: %v", err)
+		return nil, err
+	}
+	file, err := os.Create(rejectsFilePath)
+	if err != nil {
+		logger.Error("Error creating rejects file: %v", err)
+		return nil, err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	kept, err := policy.Apply(records, func(record *models.Record) error {
+		_, err := fmt.Fprintf(w, "%d: %s\n", record.Line, record.Raw)
+		return err
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		logger.Error("Error writing rejects file: %v", err)
+		return nil, err
+	}
+
+	if rejected := len(records) - matched; rejected > 0 {
+		logger.Info("Rejected %d lines to %s", rejected, rejectsFilePath)
+	}
+	return kept, nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/internal/core/patterns"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Pattern mining flags
+	patternField       string
+	patternTimeField   string
+	patternMaxClusters int
+	patternSimilarity  float64
+	patternTop         int
+	patternFormat      string
+)
+
+// patternSummary is a cluster as written in JSON output
+type patternSummary struct {
+	Pattern string     `json:"pattern"`
+	Count   int        `json:"count"`
+	First   *time.Time `json:"first,omitempty"`
+	Last    *time.Time `json:"last,omitempty"`
+	Example string     `json:"example"`
+}
+
+// patternsCmd represents the patterns command
+var patternsCmd = &cobra.Command{
+	Use:   "patterns",
+	Short: "Find the distinct message patterns in a log file",
+	Long: `Cluster log messages into patterns with their variable parts masked.
+
+Numbers are replaced by <NUM>, IP addresses by <IP> and other tokens varying
+between similar messages by <*>. With --field only the given field of records
+matching the template is clustered, otherwise whole lines are. The file is
+processed line by line and at most --max-clusters patterns are kept in memory.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if patternFormat != "table" && patternFormat != "json" {
+			return fmt.Errorf("invalid format `%s`, expected table or json", patternFormat)
+		}
+
+		miner := patterns.NewMiner(patterns.Options{
+			Similarity:  patternSimilarity,
+			MaxClusters: patternMaxClusters,
+		})
+		add := func(message string, timestamp time.Time, line string) {
+			miner.Add(message, timestamp, line)
+		}
+		if _, err := mineMessages(inputFilePath, patternField, patternTimeField, add); err != nil {
+			return err
+		}
+
+		if miner.Evicted() > 0 {
+			logger.Warn("Dropped %d rare patterns above --max-clusters", miner.Evicted())
+		}
+
+		clusters := miner.Clusters()
+		if patternTop > 0 && len(clusters) > patternTop {
+			clusters = clusters[:patternTop]
+		}
+
+		if patternFormat == "json" {
+			return printPatternsJSON(clusters)
+		}
+		return printPatternsTable(clusters)
+	},
+}
+
+// mineMessages passes the message of each line of a file to add, with the time
+// and line of its record, and returns the number of messages. With a field only
+// the field of records matching the template is passed, otherwise whole lines are.
+func mineMessages(path, field, timeField string, add func(message string, timestamp time.Time, line string)) (int, error) {
+	// Only reading a field requires a template
+	var p *parser.TemplateParser
+	if field != "" {
+		var err error
+		if p, err = newParser(); err != nil {
+			return 0, err
+		}
+	}
+
+	messages, skipped := 0, 0
+	err := newFileUtil().EachLine(path, func(line string) error {
+		if p == nil {
+			add(line, time.Time{}, line)
+			messages++
+			return nil
+		}
+
+		record := p.ParseRecord(line)
+		message, ok := record.Value(field)
+		if !record.Matched || !ok {
+			skipped++
+			return nil
+		}
+		timestamp, _ := record.Time(timeField)
+		add(message, timestamp, line)
+		messages++
+		return nil
+	})
+	if err != nil {
+		logger.Error("Error reading file: %v", err)
+		return messages, err
+	}
+
+	if skipped > 0 {
+		logger.Warn("Skipped %d lines of %s without field `%s`", skipped, path, field)
+	}
+	return messages, nil
+}
+
+// printPatternsTable prints clusters as an aligned table
+func printPatternsTable(clusters []*patterns.Cluster) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNT\tFIRST\tLAST\tPATTERN\tEXAMPLE")
+	for _, cluster := range clusters {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", cluster.Count,
+			formatTime(cluster.First), formatTime(cluster.Last), cluster.Pattern(), cluster.Example)
+	}
+	return w.Flush()
+}
+
+// printPatternsJSON prints clusters as a JSON array
+func printPatternsJSON(clusters []*patterns.Cluster) error {
+	summaries := make([]patternSummary, len(clusters))
+	for i, cluster := range clusters {
+		summaries[i] = patternSummary{
+			Pattern: cluster.Pattern(),
+			Count:   cluster.Count,
+			Example: cluster.Example,
+		}
+		if !cluster.First.IsZero() {
+			summaries[i].First, summaries[i].Last = &cluster.First, &cluster.Last
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summaries)
+}
+
+// formatTime formats a time for tables, "-" if unknown
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func init() {
+	rootCmd.AddCommand(patternsCmd)
+
+	patternsCmd.Flags().StringVarP(&inputFilePath, "input", "i", "", "Path to input file (required)")
+	patternsCmd.Flags().IntVar(&patternTop, "top", 0, "Only print the most frequent patterns, 0 for all")
+	patternsCmd.Flags().StringVar(&patternFormat, "format", "table", "Output format: table or json")
+	patternsCmd.MarkFlagFilename("input")
+	patternsCmd.MarkFlagRequired("input")
+	addMiningFlags(patternsCmd)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitKashish/golog/pkg/logger"
+)
+
+func TestPatternsCmd_JSONOutputParses(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "app.log")
+	lines := "INFO user 1 logged in\nINFO user 2 logged in\ngarbage\nERROR disk full\n"
+	if err := os.WriteFile(input, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+
+	// The logger is created after redirecting, so it picks the redirected files
+	oldStdout, oldStderr, oldLogger := os.Stdout, os.Stderr, logger.GetLogger()
+	os.Stdout, os.Stderr = stdout, stderr
+	logger.SetLogger(logger.NewLogger())
+	defer func() {
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+		logger.SetLogger(oldLogger)
+	}()
+
+	// The unmatched line is reported with a warning while printing JSON
+	rootCmd.SetArgs([]string{"patterns", "-i", input, "--format", "json",
+		"--source", "@level-level@ @msg-string@", "--target", "@msg@", "--field", "msg"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	out, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summaries []patternSummary
+	if err := json.Unmarshal(out, &summaries); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, out)
+	}
+	if len(summaries) != 2 {
+		t.Errorf("got %d patterns, want 2: %s", len(summaries), out)
+	}
+
+	diagnostics, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) == 0 {
+		t.Error("stderr is empty, want the skipped line warning")
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gitKashish/golog/internal/core/presets"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// presetsCmd represents the presets command
+var presetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "Browse built-in templates for common log formats",
+	Long: `Browse built-in templates for common log formats.
+
+Presets can be used with the --preset flag of show and write, or extended from
+template.yaml to override just some parts:
+
+  extends: nginx-combined
+  targetTemplate: "@status@ @request@"`,
+}
+
+// presetsListCmd represents the presets list command
+var presetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all built-in presets",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tALIASES\tDESCRIPTION")
+		for _, preset := range presets.List() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", preset.Name, strings.Join(preset.Aliases, ", "), preset.Description)
+		}
+		return w.Flush()
+	},
+}
+
+// presetsShowCmd represents the presets show command
+var presetsShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a preset as template.yaml",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		preset, ok := presets.Get(args[0])
+		if !ok {
+			return fmt.Errorf("unknown preset `%s`", args[0])
+		}
+
+		data, err := yaml.Marshal(map[string]string{
+			"sourceTemplate": preset.Literals.Source,
+			"targetTemplate": preset.Literals.Target,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("# %s: %s\n", preset.Name, preset.Description)
+		fmt.Printf("# Example: %s\n", preset.Example)
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(presetsCmd)
+	presetsCmd.AddCommand(presetsListCmd)
+	presetsCmd.AddCommand(presetsShowCmd)
+}
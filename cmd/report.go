@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/report"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+// reportToStderr is the value of --report printing the report to stderr
+const reportToStderr = "stderr"
+
+var (
+	// Report flags
+	reportPath  string
+	reportLines int
+)
+
+// addReportFlags adds the flags requesting an end-of-run report to a command
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportPath, "report", "", "Print a processing report to stderr, or write it as JSON to the given file")
+	cmd.Flags().Lookup("report").NoOptDefVal = reportToStderr
+	cmd.Flags().IntVar(&reportLines, "report-lines", report.DefaultMaxLines, "Number of offending line numbers listed per problem in the report")
+}
+
+// startReport starts collecting the report of a run, nil if none was requested
+func startReport() *report.Collector {
+	if reportPath == "" {
+		return nil
+	}
+	return report.NewCollector(templateName(), reportLines)
+}
+
+// collectReport adds parsed records to the report of a run, if requested
+func collectReport(collector *report.Collector, records []*models.Record) {
+	if collector == nil {
+		return
+	}
+	for _, record := range records {
+		collector.Add(record)
+	}
+}
+
+// finishReport writes the report of a run, if requested
+func finishReport(collector *report.Collector, lines, records int) error {
+	if collector == nil {
+		return nil
+	}
+
+	r := collector.Finish(lines, records)
+	if reportPath == reportToStderr {
+		return r.WriteText(os.Stderr)
+	}
+
+	file, err := os.Create(reportPath)
+	if err != nil {
+		logger.Error("Error creating report: %v", err)
+		return err
+	}
+	defer file.Close()
+
+	if err := r.WriteJSON(file); err != nil {
+		logger.Error("Error writing report: %v", err)
+		return err
+	}
+	// Closing flushes the report, so its error is a write error
+	if err := file.Close(); err != nil {
+		logger.Error("Error writing report: %v", err)
+		return err
+	}
+	logger.Info("Report written to %s", reportPath)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/gitKashish/golog/internal/config"
+	"github.com/gitKashish/golog/pkg/fileutil"
 	"github.com/gitKashish/golog/pkg/logger"
 	"github.com/spf13/cobra"
 )
@@ -13,22 +14,62 @@ var (
 	cfg *config.Config
 
 	// Command flags
-	inputFilePath string
-	verbose       bool
+	inputFilePath    string
+	verbose          bool
+	configFilePath   string
+	templateFilePath string
+
+	// Input reading flags
+	maxLineSize   string
+	longLines     string
+	inputEncoding string
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "golog",
 	Short: "A simple tool to format your logs.",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Set log level based on verbose flag
 		if verbose {
 			logger.SetLevel(logger.DEBUG)
 		}
+
+		// Load configuration from file and environment
+		var err error
+		cfg, err = config.Load(configFilePath)
+		if err != nil {
+			logger.Error("Error loading configuration: %v", err)
+			return err
+		}
+		if cfg.Path != "" {
+			logger.Debug("Using config file %s", cfg.Path)
+		}
+
+		// Command line flags take precedence over everything else
+		if cmd.Flags().Changed("template") {
+			if err := cfg.Set("template.path", templateFilePath, config.FlagSource("template")); err != nil {
+				return err
+			}
+		}
+		inputFlags := map[string]string{"max-line-size": maxLineSize, "long-lines": longLines, "encoding": inputEncoding}
+		for name, value := range inputFlags {
+			if !cmd.Flags().Changed(name) {
+				continue
+			}
+			if err := cfg.Set("input."+name, value, config.FlagSource(name)); err != nil {
+				return err
+			}
+		}
+		return nil
 	},
 }
 
+// newFileUtil creates a FileUtil reading input files as configured
+func newFileUtil() *fileutil.FileUtil {
+	return &fileutil.FileUtil{Options: cfg.ReadOptions()}
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -39,9 +80,13 @@ func Execute() {
 }
 
 func init() {
-	// Initialize configuration
-	cfg = config.NewConfig()
-
 	// Add persistent flags that are valid for all commands
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().StringVar(&configFilePath, "config", "", "Path to config file (default is discovered from .golog/golog.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&templateFilePath, "template", "t", "", "Path to template file (default is template.yaml)")
+	rootCmd.PersistentFlags().StringVar(&maxLineSize, "max-line-size", "1MiB", "Size of the longest input line, like 512KB or 4MiB (K, M and G are binary like KiB)")
+	rootCmd.PersistentFlags().StringVar(&longLines, "long-lines", fileutil.LongLinesError, "What to do with longer lines: error or truncate")
+	rootCmd.PersistentFlags().StringVar(&inputEncoding, "encoding", fileutil.EncodingAuto, "Encoding of input files: auto, utf-8, utf-16le, utf-16be or latin1")
+	rootCmd.MarkPersistentFlagFilename("config", "yaml", "yml")
+	rootCmd.MarkPersistentFlagFilename("template", "yaml", "yml")
 }
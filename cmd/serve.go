@@ -7,9 +7,11 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/gitKashish/golog/internal/config"
 	"github.com/gitKashish/golog/internal/server"
 	"github.com/gitKashish/golog/pkg/logger"
 	"github.com/spf13/cobra"
@@ -24,7 +26,9 @@ Default port is 2600 but you can specify a different port using the -p or --port
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Override port from command line if specified
 		if cmd.Flags().Changed("port") {
-			cfg.Server.Port = port
+			if err := cfg.Set("server.port", strconv.Itoa(port), config.FlagSource("port")); err != nil {
+				return err
+			}
 		}
 
 		// Create and configure server
@@ -4,8 +4,6 @@ import (
 	"fmt"
 
 	"github.com/gitKashish/golog/internal/core/formatter"
-	"github.com/gitKashish/golog/internal/core/parser"
-	"github.com/gitKashish/golog/pkg/fileutil"
 	"github.com/gitKashish/golog/pkg/logger"
 	"github.com/spf13/cobra"
 )
@@ -14,34 +12,65 @@ import (
 var showCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show formatted logs from a file",
-	Long:  `Read logs from a file and display them formatted according to the template.`,
+	Long: `Read logs from a file and display them formatted according to the template.
+
+Output is colored when printed to a terminal: levels by severity, timestamps
+dimmed and JSON highlighted. Target templates may style fields with a modifier
+like @message:bold+red@ or @level:color@, and text with markup like {red}...{/}.
+Set NO_COLOR or pass --color=never to print plain text.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Create file utility
-		fileUtil := fileutil.NewFileUtil()
+		collector := startReport()
 
-		// Read source lines from file
-		sourceLines, err := fileUtil.ReadLines(inputFilePath)
+		minimum, err := minSeverity()
+		if err != nil {
+			return err
+		}
+		where, err := whereConditions()
 		if err != nil {
-			logger.Error("Error reading file: %v", err)
 			return err
 		}
 
 		// Create parser and formatter
-		p := parser.NewTemplateParser()
-		err = p.LoadTemplate(cfg.Template.TemplatePath)
+		p, err := newParser()
 		if err != nil {
-			logger.Error("Error loading template: %v", err)
 			return err
 		}
 
+		// Color output in the terminal
+		painter, err := newPainter(cmd)
+		if err != nil {
+			logger.Error("Error setting up colors: %v", err)
+			return err
+		}
+		if painter != nil {
+			p.SetStyler(painter)
+		}
+
 		f := formatter.NewTemplateFormatter(p)
+		f.SetReadOptions(cfg.ReadOptions())
 
-		// Format and print each line
-		for _, line := range sourceLines {
-			fmt.Println(f.FormatLog(line))
+		// Read and format source lines from file
+		records, lineCount, err := f.FormatFile(inputFilePath, workers)
+		if err != nil {
+			logger.Error("Error reading file: %v", err)
+			return err
+		}
+		collectReport(collector, records)
+		records, err = handleMismatches(p, records)
+		if err != nil {
+			return err
+		}
+		records = filterSeverity(records, minimum)
+		if len(where) > 0 {
+			records = formatter.FilterWhere(records, where)
 		}
 
-		return nil
+		// Print each formatted log
+		for _, record := range records {
+			fmt.Println(record.Output)
+		}
+
+		return finishReport(collector, lineCount, len(records))
 	},
 }
 
@@ -51,4 +80,12 @@ func init() {
 	showCmd.Flags().StringVarP(&inputFilePath, "input", "i", "", "Path to input file (required)")
 	showCmd.MarkFlagFilename("input")
 	showCmd.MarkFlagRequired("input")
+
+	addTemplateFlags(showCmd)
+	addMismatchFlags(showCmd)
+	addLevelFlag(showCmd)
+	addWhereFlag(showCmd)
+	addWorkersFlag(showCmd)
+	addColorFlag(showCmd)
+	addReportFlags(showCmd)
 }
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gitKashish/golog/internal/core/fixtures"
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Template lint flags
+	lintStrict bool
+
+	// Template test flags
+	goldenDir    string
+	updateGolden bool
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Check and test template files",
+}
+
+// templateLintCmd represents the template lint command
+var templateLintCmd = &cobra.Command{
+	Use:   "lint [template.yaml]",
+	Short: "Report errors and likely mistakes in a template file",
+	Long: `Report errors and likely mistakes in a template file.
+
+Besides the errors that prevent a template from being used, lint warns about
+fields without a delimiter between them, fields not used in the target
+template, text that looks like a misspelled field, @ characters that are not
+escaped as \@ and whitespace in the source template that matches differently
+than it reads. Each issue is reported with its line and column in the file.
+
+Exits with status 1 if there are errors, or warnings with --strict.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := cfg.Template.TemplatePath
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		diagnostics, err := parser.LintFile(path)
+		if err != nil {
+			logger.Error("Error loading template: %v", err)
+			return err
+		}
+
+		lines := []string{}
+		if data, err := os.ReadFile(path); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+
+		errorCount := 0
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Severity == parser.SeverityError {
+				errorCount++
+			}
+			printDiagnostic(path, diagnostic, lines)
+		}
+		logger.Info("%d errors, %d warnings", errorCount, len(diagnostics)-errorCount)
+
+		if errorCount > 0 || (lintStrict && len(diagnostics) > 0) {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// templateTestCmd represents the template test command
+var templateTestCmd = &cobra.Command{
+	Use:   "test [template.yaml]",
+	Short: "Check that a template renders its sample lines as expected",
+	Long: `Check that a template renders its sample lines as expected.
+
+Samples are listed in the tests section of the template file, each with its
+input lines, the expected output and optionally the expected values of fields:
+
+  tests:
+    - name: login
+      input: "2024-01-02T10:00:00Z INFO user bob logged in"
+      output: "[INFO] user bob logged in"
+      fields:
+        level: INFO
+
+Input .log files in the testdata directory next to the template are rendered
+and compared with their .golden file, which --update writes with the actual
+output.
+
+Differences are shown in unified diff format. Exits with status 1 if any test
+fails.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := cfg.Template.TemplatePath
+		if len(args) > 0 {
+			path = args[0]
+		}
+		dir := goldenDir
+		if dir == "" {
+			dir = filepath.Join(filepath.Dir(path), "testdata")
+		}
+
+		literals, err := parser.LoadLiterals(path)
+		if err != nil {
+			logger.Error("Error loading template: %v", err)
+			return err
+		}
+		p := parser.NewTemplateParser()
+		p.SetVendorPaths(cfg.Stacktrace.VendorPaths)
+		if err := p.SetLiterals(literals); err != nil {
+			logger.Error("Error loading template: %v", err)
+			return err
+		}
+
+		inputs, err := fixtures.GoldenInputs(dir)
+		if err != nil {
+			logger.Error("Error reading golden files: %v", err)
+			return err
+		}
+		if len(literals.Tests) == 0 && len(inputs) == 0 {
+			logger.Warn("No tests in %s and no input files in %s", path, dir)
+			return nil
+		}
+
+		results := []fixtures.Result{}
+		for i, test := range literals.Tests {
+			if test.Name == "" {
+				test.Name = fmt.Sprintf("test %d", i+1)
+			}
+			result, err := fixtures.Run(p, test)
+			if err != nil {
+				logger.Error("Error running %s: %v", test.Name, err)
+				return err
+			}
+			results = append(results, result)
+		}
+		for _, input := range inputs {
+			result, err := fixtures.RunGolden(p, cfg.ReadOptions(), input, updateGolden)
+			if err != nil {
+				logger.Error("Error running %s: %v", input, err)
+				return err
+			}
+			results = append(results, result)
+		}
+
+		failed := 0
+		for _, result := range results {
+			if result.Passed() {
+				fmt.Printf("ok    %s\n", result.Name)
+				continue
+			}
+			failed++
+			fmt.Printf("FAIL  %s\n", result.Name)
+			for _, failure := range result.Failures {
+				fmt.Printf("      %s\n", failure)
+			}
+			fmt.Print(result.Diff)
+		}
+		if updateGolden && len(inputs) > 0 {
+			logger.Info("Updated %d golden files in %s", len(inputs), dir)
+		}
+		logger.Info("%d passed, %d failed", len(results)-failed, failed)
+
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// printDiagnostic prints a diagnostic along with the line of the file it is
+// located on and a caret pointing at it
+func printDiagnostic(path string, diagnostic parser.Diagnostic, lines []string) {
+	if diagnostic.Line == 0 || diagnostic.Line > len(lines) {
+		fmt.Printf("%s: %s: %s\n", path, diagnostic.Severity, diagnostic.Message)
+		return
+	}
+
+	fmt.Printf("%s:%d:%d: %s: %s\n", path, diagnostic.Line, diagnostic.Column, diagnostic.Severity, diagnostic.Message)
+	line := lines[diagnostic.Line-1]
+	column := min(diagnostic.Column-1, utf8.RuneCountInString(line))
+	width := max(min(diagnostic.End-diagnostic.Start, utf8.RuneCountInString(line)-column), 1)
+	fmt.Printf("  %s\n  %s^%s\n", line, strings.Repeat(" ", column), strings.Repeat("~", width-1))
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateLintCmd)
+
+	templateLintCmd.Flags().BoolVar(&lintStrict, "strict", false, "Exit with status 1 on warnings too")
+
+	templateCmd.AddCommand(templateTestCmd)
+	templateTestCmd.Flags().StringVar(&goldenDir, "golden-dir", "", "Directory of input files and their golden files (default is testdata next to the template)")
+	templateTestCmd.Flags().BoolVar(&updateGolden, "update", false, "Write golden files with the actual output")
+}
@@ -4,45 +4,79 @@ import (
 	"fmt"
 
 	"github.com/gitKashish/golog/internal/core/formatter"
-	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/output"
 	"github.com/gitKashish/golog/pkg/fileutil"
 	"github.com/gitKashish/golog/pkg/logger"
 	"github.com/spf13/cobra"
 )
 
 var (
-	outputFilePath string
-	showOutput     bool
+	outputFilePath    string
+	showOutput        bool
+	splitBy           []string
+	maxOpenFiles      int
+	maxOutputSize     string
+	unmatchedFilePath string
 )
 
 // writeCmd represents the write command
 var writeCmd = &cobra.Command{
 	Use:   "write",
 	Short: "Write formatted logs to a file",
-	Long:  `Read logs from a file, format them according to the template, and write them to another file.`,
+	Long: `Read logs from a file, format them according to the template, and write them to another file.
+
+Records can be routed into multiple files by field value, either with --split-by
+or with a path template like -o 'out/@module@/@level@.log'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Create file utility
-		fileUtil := fileutil.NewFileUtil()
+		collector := startReport()
 
-		// Read source lines from file
-		sourceLines, err := fileUtil.ReadLines(inputFilePath)
+		minimum, err := minSeverity()
+		if err != nil {
+			return err
+		}
+		where, err := whereConditions()
 		if err != nil {
-			logger.Error("Error reading file: %v", err)
 			return err
 		}
 
 		// Create parser and formatter
-		p := parser.NewTemplateParser()
-		err = p.LoadTemplate(cfg.Template.TemplatePath)
+		p, err := newParser()
 		if err != nil {
-			logger.Error("Error loading template: %v", err)
 			return err
 		}
 
 		f := formatter.NewTemplateFormatter(p)
+		f.SetReadOptions(cfg.ReadOptions())
+
+		// Read and format source lines from file
+		records, lineCount, err := f.FormatFile(inputFilePath, workers)
+		if err != nil {
+			logger.Error("Error reading file: %v", err)
+			return err
+		}
+		collectReport(collector, records)
+		records, err = handleMismatches(p, records)
+		if err != nil {
+			return err
+		}
+		records = filterSeverity(records, minimum)
+		if len(where) > 0 {
+			records = formatter.FilterWhere(records, where)
+		}
 
-		// Format logs
-		formattedLogs := f.FormatLogs(sourceLines)
+		// Route records into multiple files if requested
+		if len(splitBy) > 0 || output.IsPathTemplate(outputFilePath) {
+			if err := writeRouted(records); err != nil {
+				return err
+			}
+			return finishReport(collector, lineCount, len(records))
+		}
+
+		formattedLogs := make([]string, len(records))
+		for i, record := range records {
+			formattedLogs[i] = record.Output
+		}
 
 		// Show output if requested
 		if showOutput {
@@ -52,17 +86,61 @@ var writeCmd = &cobra.Command{
 		}
 
 		// Write to output file
-		err = fileUtil.WriteLines(formattedLogs, outputFilePath)
+		err = fileutil.NewFileUtil().WriteLines(formattedLogs, outputFilePath)
 		if err != nil {
 			logger.Error("Error writing to file: %v", err)
 			return err
 		}
 
 		logger.Info("Logs written to %s", outputFilePath)
-		return nil
+		return finishReport(collector, lineCount, len(records))
 	},
 }
 
+// writeRouted writes records into the files derived from their field values
+func writeRouted(records []*models.Record) error {
+	pathTemplate := outputFilePath
+	if len(splitBy) > 0 {
+		pathTemplate = output.PathTemplate(outputFilePath, splitBy)
+	}
+
+	maxSize := int64(0)
+	if maxOutputSize != "" {
+		size, err := fileutil.ParseSize(maxOutputSize)
+		if err != nil {
+			logger.Error("Error parsing max size: %v", err)
+			return err
+		}
+		maxSize = size
+	}
+
+	router := output.NewRouter(output.Options{
+		PathTemplate:  pathTemplate,
+		MaxOpenFiles:  maxOpenFiles,
+		MaxSize:       maxSize,
+		UnmatchedPath: unmatchedFilePath,
+	})
+
+	for _, record := range records {
+		if showOutput {
+			fmt.Println(record.Output)
+		}
+		if err := router.Write(record); err != nil {
+			router.Close()
+			logger.Error("Error writing to file: %v", err)
+			return err
+		}
+	}
+
+	if err := router.Close(); err != nil {
+		logger.Error("Error closing files: %v", err)
+		return err
+	}
+
+	logger.Info("Logs written to %d files", len(router.Paths()))
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(writeCmd)
 
@@ -70,9 +148,22 @@ func init() {
 	writeCmd.MarkFlagFilename("input")
 	writeCmd.MarkFlagRequired("input")
 
+	addTemplateFlags(writeCmd)
+	addMismatchFlags(writeCmd)
+	addLevelFlag(writeCmd)
+	addWhereFlag(writeCmd)
+	addWorkersFlag(writeCmd)
+	addReportFlags(writeCmd)
+
 	writeCmd.Flags().StringVarP(&outputFilePath, "output", "o", "", "Path to output file (required)")
 	writeCmd.MarkFlagRequired("output")
 	writeCmd.MarkFlagFilename("output")
 
 	writeCmd.Flags().BoolVarP(&showOutput, "show", "s", false, "Show output on console")
+
+	writeCmd.Flags().StringSliceVar(&splitBy, "split-by", nil, "Split output into one file per value of the given fields, using --output as directory")
+	writeCmd.Flags().IntVar(&maxOpenFiles, "max-open-files", output.DefaultMaxOpenFiles, "Maximum number of output files kept open at once")
+	writeCmd.Flags().StringVar(&maxOutputSize, "max-size", "", "Rotate split output files once they exceed this size (e.g. 10MB, or 10M for 10MiB)")
+	writeCmd.Flags().StringVar(&unmatchedFilePath, "unmatched", "", "Path to the file receiving unmatched lines when splitting output")
+	writeCmd.MarkFlagFilename("unmatched")
 }
@@ -1,16 +1,42 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/color"
+	"github.com/gitKashish/golog/internal/core/stacktrace"
+	"github.com/gitKashish/golog/pkg/fileutil"
+	"gopkg.in/yaml.v3"
 )
 
+// FileName is the name of the golog configuration file
+const FileName = "golog.yaml"
+
+// DirName is the name of the project level directory holding golog files
+const DirName = ".golog"
+
 // Config holds all configuration for the application
 type Config struct {
 	// Server configuration
 	Server ServerConfig
 	// Template configuration
 	Template TemplateConfig
+	// Input configuration
+	Input InputConfig
+	// Color configuration
+	Color ColorConfig
+	// Stack trace configuration
+	Stacktrace StacktraceConfig
+	// Path to the configuration file in use, empty if none was found
+	Path string
+
+	sources map[string]Source // Source of each option by key
 }
 
 // ServerConfig holds configuration for the HTTP server
@@ -25,33 +51,424 @@ type TemplateConfig struct {
 	TemplatePath string
 }
 
-// NewConfig creates a new configuration with default values
-// and overrides from environment variables
-func NewConfig() *Config {
-	return &Config{
+// InputConfig holds configuration for reading input files
+type InputConfig struct {
+	// Size in bytes of the longest line read
+	MaxLineSize int64
+	// What to do with longer lines: error or truncate
+	LongLines string
+	// Encoding of input files, auto to detect it from a byte order mark
+	Encoding string
+}
+
+// ColorConfig holds configuration for colored output
+type ColorConfig struct {
+	// When to color output: auto, always or never
+	Mode string
+	// Style of each role of colored output, like "bold red" for level.error
+	Theme color.Theme
+}
+
+// StacktraceConfig holds configuration for fields of the stacktrace type
+type StacktraceConfig struct {
+	// Paths of frames folded when rendering stack traces, like node_modules/
+	VendorPaths []string
+}
+
+// Source describes where a configuration value came from
+type Source struct {
+	Kind   string // "default", "file", "env", "flag" or "discovered"
+	Detail string // File path, variable or flag name the value was read from
+}
+
+// String returns a human readable representation of a Source
+func (s Source) String() string {
+	if s.Detail == "" {
+		return s.Kind
+	}
+	return s.Kind + " " + s.Detail
+}
+
+// Value is a single effective configuration value
+type Value struct {
+	Key    string
+	Value  string
+	Source Source
+}
+
+// option describes a configuration option that can be set from any source
+type option struct {
+	key     string                      // Key in the configuration file like "server.port"
+	aliases []string                    // Additional environment variables kept for compatibility
+	path    bool                        // Value is a path resolved relative to the configuration file
+	get     func(*Config) string        // Returns the current value as a string
+	set     func(*Config, string) error // Parses and stores a value
+}
+
+// options lists all configuration options in display order
+var options = []option{
+	{
+		key:     "server.port",
+		aliases: []string{"PORT"},
+		get:     func(c *Config) string { return strconv.Itoa(c.Server.Port) },
+		set: func(c *Config, value string) error {
+			port, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid port `%s`", value)
+			}
+			c.Server.Port = port
+			return nil
+		},
+	},
+	{
+		key:  "template.path",
+		path: true,
+		get:  func(c *Config) string { return c.Template.TemplatePath },
+		set: func(c *Config, value string) error {
+			c.Template.TemplatePath = value
+			return nil
+		},
+	},
+	{
+		key: "input.max-line-size",
+		get: func(c *Config) string { return strconv.FormatInt(c.Input.MaxLineSize, 10) },
+		set: func(c *Config, value string) error {
+			size, err := fileutil.ParseSize(value)
+			if err != nil || size == 0 {
+				return fmt.Errorf("invalid size `%s`", value)
+			}
+			c.Input.MaxLineSize = size
+			return nil
+		},
+	},
+	{
+		key: "input.long-lines",
+		get: func(c *Config) string { return c.Input.LongLines },
+		set: func(c *Config, value string) error {
+			if !fileutil.IsLongLinesPolicy(value) {
+				return fmt.Errorf("invalid policy `%s`, expected error or truncate", value)
+			}
+			c.Input.LongLines = value
+			return nil
+		},
+	},
+	{
+		key: "input.encoding",
+		get: func(c *Config) string { return c.Input.Encoding },
+		set: func(c *Config, value string) error {
+			value = strings.ToLower(value)
+			if !fileutil.IsEncoding(value) {
+				return fmt.Errorf("invalid encoding `%s`, expected auto, utf-8, utf-16le, utf-16be or latin1", value)
+			}
+			c.Input.Encoding = value
+			return nil
+		},
+	},
+	{
+		key: "color.mode",
+		get: func(c *Config) string { return c.Color.Mode },
+		set: func(c *Config, value string) error {
+			if value != color.ModeAuto && value != color.ModeAlways && value != color.ModeNever {
+				return fmt.Errorf("invalid color mode `%s`, expected auto, always or never", value)
+			}
+			c.Color.Mode = value
+			return nil
+		},
+	},
+	{
+		key: "stacktrace.vendor-paths",
+		get: func(c *Config) string { return strings.Join(c.Stacktrace.VendorPaths, ",") },
+		set: func(c *Config, value string) error {
+			paths := []string{}
+			for _, path := range strings.Split(value, ",") {
+				if path = strings.TrimSpace(path); path != "" {
+					paths = append(paths, path)
+				}
+			}
+			c.Stacktrace.VendorPaths = paths
+			return nil
+		},
+	},
+}
+
+func init() {
+	// Every role of the theme is an option of its own, like color.theme.level.error
+	for _, role := range color.Roles() {
+		options = append(options, option{
+			key: "color.theme." + role,
+			get: func(c *Config) string { return c.Color.Theme[role] },
+			set: func(c *Config, value string) error {
+				if _, err := color.ParseStyle(value); err != nil {
+					return err
+				}
+				c.Color.Theme[role] = value
+				return nil
+			},
+		})
+	}
+}
+
+// Load creates a new configuration from default values, the configuration file
+// and environment variables, in increasing order of precedence. If configPath is
+// empty the configuration file is discovered from GOLOG_CONFIG, the .golog
+// directory of the working directory or any of its parents, and finally the
+// user configuration directory.
+func Load(configPath string) (*Config, error) {
+	cfg := defaultConfig()
+
+	if configPath == "" {
+		configPath = os.Getenv("GOLOG_CONFIG")
+	}
+	if configPath == "" {
+		configPath = discoverConfig()
+	}
+
+	if configPath != "" {
+		if err := cfg.loadFile(configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if cfg.sources["template.path"].Kind == "default" {
+		cfg.discoverTemplate()
+	}
+	return cfg, nil
+}
+
+// Set overrides the value of an option, recording where it came from
+func (c *Config) Set(key, value string, source Source) error {
+	for _, opt := range options {
+		if opt.key == key {
+			if err := opt.set(c, value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			c.sources[key] = source
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown configuration key `%s`", key)
+}
+
+// Values returns the effective value and source of every option
+func (c *Config) Values() []Value {
+	values := make([]Value, len(options))
+	for i, opt := range options {
+		values[i] = Value{Key: opt.key, Value: opt.get(c), Source: c.sources[opt.key]}
+	}
+	return values
+}
+
+// FlagSource returns the source of a value set from a command line flag
+func FlagSource(name string) Source {
+	return Source{Kind: "flag", Detail: "--" + name}
+}
+
+// EnvName returns the environment variable for a configuration key
+func EnvName(key string) string {
+	return "GOLOG_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// defaultConfig creates a new configuration holding only default values
+func defaultConfig() *Config {
+	cfg := &Config{
 		Server: ServerConfig{
-			Port: getEnvInt("PORT", 2600),
+			Port: 2600,
 		},
 		Template: TemplateConfig{
-			TemplatePath: getEnvString("GOLOG_TEMPLATE_PATH", "template.yaml"),
+			TemplatePath: "template.yaml",
+		},
+		Input: InputConfig{
+			MaxLineSize: fileutil.DefaultMaxLineSize,
+			LongLines:   fileutil.LongLinesError,
+			Encoding:    fileutil.EncodingAuto,
+		},
+		Color: ColorConfig{
+			Mode:  color.ModeAuto,
+			Theme: color.Theme{},
+		},
+		Stacktrace: StacktraceConfig{
+			VendorPaths: slices.Clone(stacktrace.DefaultVendorPaths),
 		},
+		sources: make(map[string]Source),
 	}
+	for role, style := range color.DefaultTheme {
+		cfg.Color.Theme[role] = style
+	}
+	for _, opt := range options {
+		cfg.sources[opt.key] = Source{Kind: "default"}
+	}
+	return cfg
 }
 
-// Helper function to get an environment variable as an integer
-func getEnvInt(key string, defaultValue int) int {
-	if value, exists := os.LookupEnv(key); exists {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
+// loadFile applies the values of a configuration file
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	raw := map[string]any{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	values := map[string]string{}
+	flatten("", raw, values)
+
+	// Apply values in a stable order so errors are reported deterministically
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	source := Source{Kind: "file", Detail: path}
+	for _, key := range keys {
+		value := values[key]
+		if isPathOption(key) && value != "" && !filepath.IsAbs(value) {
+			value = filepath.Join(filepath.Dir(path), value)
+		}
+		if err := c.Set(key, value, source); err != nil {
+			return fmt.Errorf("error in config file %s: %w", path, err)
 		}
 	}
-	return defaultValue
+
+	c.Path = path
+	return nil
+}
+
+// applyEnv applies values from GOLOG_* environment variables and their aliases
+func (c *Config) applyEnv() error {
+	for _, opt := range options {
+		for _, name := range append([]string{EnvName(opt.key)}, opt.aliases...) {
+			value, exists := os.LookupEnv(name)
+			if !exists {
+				continue
+			}
+			if err := c.Set(opt.key, value, Source{Kind: "env", Detail: name}); err != nil {
+				return fmt.Errorf("error in environment variable %s: %w", name, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// discoverTemplate looks for a template file when none was configured explicitly
+func (c *Config) discoverTemplate() {
+	candidates := []string{"template.yaml"}
+	if c.Path != "" {
+		candidates = append(candidates, filepath.Join(filepath.Dir(c.Path), "template.yaml"))
+	}
+	candidates = append(candidates, searchPaths("template.yaml")...)
+
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			if candidate != c.Template.TemplatePath {
+				c.Template.TemplatePath = candidate
+				c.sources["template.path"] = Source{Kind: "discovered", Detail: candidate}
+			}
+			return
+		}
+	}
+}
+
+// discoverConfig returns the first configuration file found in the search paths
+func discoverConfig() string {
+	for _, candidate := range searchPaths(FileName) {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// searchPaths returns the locations of a golog file in order of precedence:
+// the .golog directory of the working directory and each of its parents,
+// followed by the user configuration directory
+func searchPaths(name string) []string {
+	paths := []string{}
+
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			paths = append(paths, filepath.Join(dir, DirName, name))
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	if dir := userConfigDir(); dir != "" {
+		paths = append(paths, filepath.Join(dir, "golog", name))
+	}
+	return paths
+}
+
+// userConfigDir returns $XDG_CONFIG_HOME, falling back to ~/.config
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config")
+	}
+	return ""
+}
+
+// flatten converts nested configuration maps into dotted keys, and lists into
+// comma separated values
+func flatten(prefix string, raw map[string]any, values map[string]string) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flatten(key, nested, values)
+			continue
+		}
+		if value == nil {
+			values[key] = ""
+			continue
+		}
+		if list, ok := value.([]any); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+			continue
+		}
+		values[key] = fmt.Sprint(value)
+	}
+}
+
+// isPathOption checks whether an option holds a file path
+func isPathOption(key string) bool {
+	for _, opt := range options {
+		if opt.key == key {
+			return opt.path
+		}
+	}
+	return false
+}
+
+// fileExists checks if a file exists and is not a directory
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
 }
 
-// Helper function to get an environment variable as a string
-func getEnvString(key string, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+// ReadOptions returns the options for reading input files
+func (c *Config) ReadOptions() fileutil.ReadOptions {
+	return fileutil.ReadOptions{
+		MaxLineSize: c.Input.MaxLineSize,
+		LongLines:   c.Input.LongLines,
+		Encoding:    c.Input.Encoding,
 	}
-	return defaultValue
 }
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeFile creates a file with the given content, creating parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Server.Port != 2600 || cfg.Template.TemplatePath != "template.yaml" {
+		t.Errorf("Load() = %+v, want default values", cfg)
+	}
+	for _, value := range cfg.Values() {
+		if value.Source.Kind != "default" {
+			t.Errorf("Load() source of %s = %v, want default", value.Key, value.Source)
+		}
+	}
+}
+
+func TestLoad_DiscoverFromParent(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, DirName, FileName), "server:\n  port: 3000\ntemplate:\n  path: logs.yaml\n")
+
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	t.Chdir(nested)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Path != filepath.Join(root, DirName, FileName) {
+		t.Errorf("Load() path = %v, want config from parent directory", cfg.Path)
+	}
+	if cfg.Server.Port != 3000 {
+		t.Errorf("Load() port = %v, want 3000", cfg.Server.Port)
+	}
+	// Relative paths are resolved against the config file
+	if want := filepath.Join(root, DirName, "logs.yaml"); cfg.Template.TemplatePath != want {
+		t.Errorf("Load() template path = %v, want %v", cfg.Template.TemplatePath, want)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "custom.yaml")
+	writeFile(t, configPath, "server:\n  port: 3000\n")
+	t.Chdir(dir)
+	t.Setenv("GOLOG_SERVER_PORT", "4000")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Server.Port != 4000 {
+		t.Errorf("Load() port = %v, want environment to override file", cfg.Server.Port)
+	}
+
+	if err := cfg.Set("server.port", "5000", FlagSource("port")); err != nil {
+		t.Fatalf("Config.Set() error = %v", err)
+	}
+	if cfg.Server.Port != 5000 || cfg.Values()[0].Source.String() != "flag --port" {
+		t.Errorf("Config.Set() = %v from %v, want flag to override environment", cfg.Server.Port, cfg.Values()[0].Source)
+	}
+}
+
+func TestLoad_Input(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, FileName)
+	writeFile(t, configPath, "input:\n  max-line-size: 4MiB\n  long-lines: truncate\n")
+	t.Chdir(dir)
+	t.Setenv("GOLOG_INPUT_ENCODING", "Latin1")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	options := cfg.ReadOptions()
+	if options.MaxLineSize != 4<<20 || options.LongLines != "truncate" || options.Encoding != "latin1" {
+		t.Errorf("Load() read options = %+v, want values from file and environment", options)
+	}
+}
+
+func TestLoad_Theme(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, FileName)
+	writeFile(t, configPath, "color:\n  mode: always\n  theme:\n    level:\n      error: bold magenta\n")
+	t.Chdir(dir)
+	t.Setenv("GOLOG_COLOR_THEME_TIMESTAMP", "gray")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Color.Mode != "always" || cfg.Color.Theme["level.error"] != "bold magenta" || cfg.Color.Theme["timestamp"] != "gray" {
+		t.Errorf("Load() color = %+v, want values from file and environment", cfg.Color)
+	}
+	// Roles not configured keep their default style
+	if cfg.Color.Theme["level.warn"] != "yellow" {
+		t.Errorf("Load() theme level.warn = %q, want default", cfg.Color.Theme["level.warn"])
+	}
+}
+
+func TestLoad_VendorPaths(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, FileName)
+	writeFile(t, configPath, "stacktrace:\n  vendor-paths:\n    - node_modules/\n    - /vendor/\n")
+	t.Chdir(dir)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"node_modules/", "/vendor/"}; !slices.Equal(cfg.Stacktrace.VendorPaths, want) {
+		t.Errorf("Load() vendor paths = %q, want %q", cfg.Stacktrace.VendorPaths, want)
+	}
+
+	// Comma separated values and an empty value turning folding off
+	t.Setenv("GOLOG_STACKTRACE_VENDOR_PATHS", " /a/, /b/ ")
+	if cfg, err = Load(configPath); err != nil || !slices.Equal(cfg.Stacktrace.VendorPaths, []string{"/a/", "/b/"}) {
+		t.Errorf("Load() vendor paths = %q, %v, want values from the environment", cfg.Stacktrace.VendorPaths, err)
+	}
+	t.Setenv("GOLOG_STACKTRACE_VENDOR_PATHS", "")
+	if cfg, err = Load(configPath); err != nil || len(cfg.Stacktrace.VendorPaths) != 0 {
+		t.Errorf("Load() vendor paths = %q, %v, want none", cfg.Stacktrace.VendorPaths, err)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "Unknown key", content: "server:\n  host: localhost\n"},
+		{name: "Invalid port", content: "server:\n  port: abc\n"},
+		{name: "Invalid yaml", content: "server: [\n"},
+		{name: "Invalid line size", content: "input:\n  max-line-size: 0\n"},
+		{name: "Invalid long lines policy", content: "input:\n  long-lines: skip\n"},
+		{name: "Invalid encoding", content: "input:\n  encoding: ebcdic\n"},
+		{name: "Invalid color mode", content: "color:\n  mode: sometimes\n"},
+		{name: "Invalid theme style", content: "color:\n  theme:\n    timestamp: pink\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), FileName)
+			writeFile(t, configPath, tt.content)
+
+			if _, err := Load(configPath); err == nil {
+				t.Errorf("Load() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package baseline
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/patterns"
+)
+
+// Version is the format version of baseline files
+const Version = 1
+
+// DefaultThreshold is the factor by which the rate of a pattern must change to be reported
+const DefaultThreshold = 2.0
+
+// gzipMagic are the first bytes of gzip files
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Kinds of changes from the baseline
+const (
+	ChangeNew       = "new"       // Pattern not seen in the baseline
+	ChangeIncreased = "increased" // Pattern seen more often than in the baseline
+	ChangeDecreased = "decreased" // Pattern seen less often than in the baseline
+	ChangeGone      = "gone"      // Pattern of the baseline not seen anymore
+)
+
+// Baseline holds the message patterns of known logs and how often they occur
+type Baseline struct {
+	Version  int       `json:"version"`
+	Created  time.Time `json:"created"`
+	Field    string    `json:"field,omitempty"` // Field the messages were read from, whole lines if empty
+	Messages int       `json:"messages"`        // Number of messages the patterns were mined from
+	Patterns []Pattern `json:"patterns"`
+}
+
+// Pattern is a message pattern of a baseline
+type Pattern struct {
+	Pattern string `json:"pattern"`
+	Count   int    `json:"count"`
+	Example string `json:"example"`
+}
+
+// Rate returns the share of messages following a pattern
+func (b *Baseline) Rate(count int) float64 {
+	if b.Messages == 0 {
+		return 0
+	}
+	return float64(count) / float64(b.Messages)
+}
+
+// New creates a baseline from the clusters of a miner
+func New(miner *patterns.Miner, field string, messages int) *Baseline {
+	b := &Baseline{Version: Version, Created: time.Now().UTC(), Field: field, Messages: messages}
+	for _, cluster := range miner.Clusters() {
+		if cluster.Count > 0 {
+			b.Patterns = append(b.Patterns, Pattern{Pattern: cluster.Pattern(), Count: cluster.Count, Example: cluster.Example})
+		}
+	}
+	return b
+}
+
+// Save writes a baseline to a gzip compressed JSON file
+func Save(b *Baseline, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating baseline file: %w", err)
+	}
+	defer file.Close()
+
+	writer := gzip.NewWriter(file)
+	if err := json.NewEncoder(writer).Encode(b); err != nil {
+		return fmt.Errorf("error writing baseline file: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error writing baseline file: %w", err)
+	}
+	return file.Close()
+}
+
+// Load reads a baseline file written by Save
+func Load(path string) (*Baseline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening baseline file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading baseline file: %w", err)
+	}
+
+	b := &Baseline{}
+	if err := json.NewDecoder(reader).Decode(b); err != nil {
+		return nil, fmt.Errorf("error parsing baseline file: %w", err)
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported baseline version %d", b.Version)
+	}
+	return b, nil
+}
+
+// IsFile checks whether a file is a baseline file rather than a log file
+func IsFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, len(gzipMagic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, gzipMagic)
+}
+
+// Change is a difference of a pattern's rate from the baseline
+type Change struct {
+	Kind      string    `json:"kind"`
+	Pattern   string    `json:"pattern"`
+	BaseCount int       `json:"baseCount"`
+	Count     int       `json:"count"`
+	BaseRate  float64   `json:"baseRate"` // Share of baseline messages following the pattern
+	Rate      float64   `json:"rate"`     // Share of compared messages following the pattern
+	First     time.Time `json:"first,omitzero"`
+	Example   string    `json:"example"`
+}
+
+// Comparer compares messages to a baseline. Messages are clustered together with
+// the baseline patterns, so messages of a known pattern join its cluster.
+type Comparer struct {
+	base     *Baseline
+	miner    *patterns.Miner
+	seeded   map[*patterns.Cluster]Pattern // Clusters of baseline patterns
+	messages int
+}
+
+// NewComparer creates a Comparer for a baseline
+func NewComparer(base *Baseline, options patterns.Options) *Comparer {
+	// Baseline patterns are seeded, so they are never evicted and only new
+	// patterns count towards MaxClusters
+	c := &Comparer{
+		base:   base,
+		miner:  patterns.NewMiner(options),
+		seeded: make(map[*patterns.Cluster]Pattern),
+	}
+	for _, pattern := range base.Patterns {
+		c.seeded[c.miner.Seed(pattern.Pattern, pattern.Example)] = pattern
+	}
+	return c
+}
+
+// Add adds a message to compare
+func (c *Comparer) Add(message string, timestamp time.Time, example string) {
+	c.miner.Add(message, timestamp, example)
+	c.messages++
+}
+
+// Changes returns the new patterns, followed by the patterns whose rate changed by
+// at least the threshold factor and the baseline patterns not seen anymore.
+// Patterns seen fewer than minCount times in both are ignored.
+func (c *Comparer) Changes(threshold float64, minCount int) []Change {
+	if threshold <= 1 {
+		threshold = DefaultThreshold
+	}
+
+	changes := []Change{}
+	for _, cluster := range c.miner.Clusters() {
+		base, known := c.seeded[cluster]
+		if max(cluster.Count, base.Count) < minCount {
+			continue
+		}
+
+		change := Change{
+			Pattern:   cluster.Pattern(),
+			BaseCount: base.Count,
+			Count:     cluster.Count,
+			BaseRate:  c.base.Rate(base.Count),
+			Rate:      c.rate(cluster.Count),
+			First:     cluster.First,
+			Example:   cluster.Example,
+		}
+		switch {
+		case !known:
+			change.Kind = ChangeNew
+		case cluster.Count == 0:
+			change.Kind = ChangeGone
+		case change.Rate >= change.BaseRate*threshold:
+			change.Kind = ChangeIncreased
+		case change.Rate <= change.BaseRate/threshold:
+			change.Kind = ChangeDecreased
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	order := map[string]int{ChangeNew: 0, ChangeIncreased: 1, ChangeDecreased: 2, ChangeGone: 3}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return order[changes[i].Kind] < order[changes[j].Kind]
+	})
+	return changes
+}
+
+// rate returns the share of compared messages holding count messages
+func (c *Comparer) rate(count int) float64 {
+	if c.messages == 0 {
+		return 0
+	}
+	return float64(count) / float64(c.messages)
+}
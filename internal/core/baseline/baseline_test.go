@@ -0,0 +1,177 @@
+package baseline
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/patterns"
+)
+
+// mine creates a baseline from messages
+func mine(messages []string) *Baseline {
+	miner := patterns.NewMiner(patterns.Options{})
+	for _, message := range messages {
+		miner.Add(message, time.Time{}, message)
+	}
+	return New(miner, "message", len(messages))
+}
+
+func TestSaveLoad(t *testing.T) {
+	b := mine([]string{"user alice logged in", "user bob logged in", "cache warmed"})
+	path := filepath.Join(t.TempDir(), "baseline.golog")
+
+	if err := Save(b, path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if !IsFile(path) {
+		t.Errorf("IsFile() = false, want true for a saved baseline")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Patterns, b.Patterns) || loaded.Messages != 3 || loaded.Field != "message" {
+		t.Errorf("Load() = %+v, want %+v", loaded, b)
+	}
+}
+
+func TestIsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("user alice logged in\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if IsFile(path) {
+		t.Errorf("IsFile() = true, want false for a log file")
+	}
+	if _, err := Load(path); err == nil {
+		t.Errorf("Load() error = nil, want error for a log file")
+	}
+}
+
+func TestComparer_Changes(t *testing.T) {
+	base := mine([]string{
+		"user alice logged in from 10.0.0.1",
+		"user bob logged in from 10.0.0.2",
+		"user carol logged in from 10.0.0.3",
+		"request took 12ms",
+		"request took 15ms",
+		"request took 11ms",
+		"cache warmed",
+		"queue drained",
+	})
+
+	comparer := NewComparer(base, patterns.Options{})
+	for _, message := range []string{
+		"user dave logged in from 10.0.0.4",
+		"request took 10ms",
+		"request took 13ms",
+		"request took 14ms",
+		"request took 18ms",
+		"disk almost full",
+		"disk almost full",
+		"queue drained",
+	} {
+		comparer.Add(message, time.Time{}, message)
+	}
+
+	type change struct {
+		kind    string
+		pattern string
+	}
+	tests := []struct {
+		name      string
+		threshold float64
+		minCount  int
+		want      []change
+	}{
+		{
+			name:      "Default threshold",
+			threshold: DefaultThreshold,
+			minCount:  1,
+			want: []change{
+				{ChangeNew, "disk almost full"},
+				{ChangeDecreased, "user <*> logged in from <IP>"},
+				{ChangeGone, "cache warmed"},
+			},
+		},
+		{
+			name:      "Lower threshold",
+			threshold: 1.3,
+			minCount:  1,
+			want: []change{
+				{ChangeNew, "disk almost full"},
+				{ChangeIncreased, "request took <NUM>"},
+				{ChangeDecreased, "user <*> logged in from <IP>"},
+				{ChangeGone, "cache warmed"},
+			},
+		},
+		{
+			name:      "Minimum count",
+			threshold: DefaultThreshold,
+			minCount:  2,
+			want: []change{
+				{ChangeNew, "disk almost full"},
+				{ChangeDecreased, "user <*> logged in from <IP>"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []change{}
+			for _, c := range comparer.Changes(tt.threshold, tt.minCount) {
+				got = append(got, change{c.Kind, c.Pattern})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Comparer.Changes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparer_SimilarPattern(t *testing.T) {
+	base := mine([]string{"user alice logged in", "user bob logged in"})
+
+	// Messages similar to a baseline pattern but not following it are new
+	comparer := NewComparer(base, patterns.Options{})
+	for _, message := range []string{"user carol logged out", "user dave logged out", "user erin logged in", "user finn logged in", "user gus logged in", "user hal logged in"} {
+		comparer.Add(message, time.Time{}, message)
+	}
+
+	changes := comparer.Changes(DefaultThreshold, 1)
+	if len(changes) != 1 || changes[0].Kind != ChangeNew || changes[0].Pattern != "user <*> logged out" || changes[0].Count != 2 {
+		t.Errorf("Comparer.Changes() = %+v, want the new pattern user <*> logged out x2", changes)
+	}
+}
+
+func TestComparer_MaxClusters(t *testing.T) {
+	base := mine([]string{"cache warmed", "queue drained"})
+
+	// More new patterns than MaxClusters evict new patterns, not baseline ones
+	comparer := NewComparer(base, patterns.Options{MaxClusters: 2})
+	for _, message := range []string{"queue drained", "queue drained", "disk almost full", "socket closed early", "retry scheduled soon now", "shard moved"} {
+		comparer.Add(message, time.Time{}, message)
+	}
+
+	type change struct {
+		kind    string
+		pattern string
+	}
+	want := []change{
+		{ChangeNew, "retry scheduled soon now"},
+		{ChangeNew, "shard moved"},
+		{ChangeGone, "cache warmed"},
+	}
+	got := []change{}
+	for _, c := range comparer.Changes(DefaultThreshold, 1) {
+		got = append(got, change{c.Kind, c.Pattern})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Comparer.Changes() = %v, want %v", got, want)
+	}
+}
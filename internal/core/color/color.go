@@ -0,0 +1,284 @@
+package color
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// Color modes
+const (
+	ModeAuto   = "auto"   // Color when writing to a terminal, unless NO_COLOR is set
+	ModeAlways = "always" // Always color
+	ModeNever  = "never"  // Never color
+)
+
+// Auto is the modifier of a target field colored according to its value and type, like @level:color@
+const Auto = "color"
+
+// reset ends all styles
+const reset = "\x1b[0m"
+
+// attributes maps the words of a style to their SGR parameter
+var attributes = map[string]string{
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+}
+
+// DefaultTheme maps the roles of styled output to their style
+var DefaultTheme = Theme{
+	"level.fatal":  "bold red",
+	"level.error":  "red",
+	"level.warn":   "yellow",
+	"level.info":   "green",
+	"level.debug":  "blue",
+	"level.trace":  "gray",
+	"timestamp":    "dim",
+	"json.key":     "cyan",
+	"json.string":  "green",
+	"json.number":  "yellow",
+	"json.literal": "magenta",
+}
+
+// markupRegex matches the tags of target template markup like {red} and {/}
+var markupRegex = regexp.MustCompile(`\{([\w.+ /]+)\}`)
+
+// Theme maps the roles of styled output, like level.error or json.key, to a
+// style of space separated words like "bold red"
+type Theme map[string]string
+
+// Roles returns the roles of the default theme in order
+func Roles() []string {
+	roles := make([]string, 0, len(DefaultTheme))
+	for role := range DefaultTheme {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// ParseStyle returns the SGR escape sequence of a style like "bold red" or
+// "bold+red"
+func ParseStyle(style string) (string, error) {
+	words := strings.FieldsFunc(style, func(r rune) bool { return r == ' ' || r == '+' })
+	if len(words) == 0 {
+		return "", fmt.Errorf("empty style")
+	}
+
+	codes := make([]string, len(words))
+	for i, word := range words {
+		code, ok := attributes[strings.ToLower(word)]
+		if !ok {
+			return "", fmt.Errorf("unknown style `%s`", word)
+		}
+		codes[i] = code
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m", nil
+}
+
+// IsStyle reports whether a modifier of a target field or a markup tag names a
+// style: a role of the theme, a style like "bold+red" or Auto
+func IsStyle(name string) bool {
+	return name == Auto || isMarkup(name)
+}
+
+// isMarkup reports whether name is a role of the theme or a style, as used in
+// markup tags
+func isMarkup(name string) bool {
+	if _, ok := DefaultTheme[name]; ok {
+		return true
+	}
+	_, err := ParseStyle(name)
+	return err == nil
+}
+
+// Severity returns the level role of a value like "WARN" or "error", empty if
+// the value is not a level
+func Severity(value string) string {
+	if severity, ok := models.ParseSeverity(value); ok {
+		return "level." + strings.ToLower(severity.String())
+	}
+	return ""
+}
+
+// Enabled decides whether output written to a file is colored in a mode
+func Enabled(mode string, file *os.File) (bool, error) {
+	switch mode {
+	case ModeAlways:
+		return true, nil
+	case ModeNever:
+		return false, nil
+	case ModeAuto, "":
+		if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+			return false, nil
+		}
+		info, err := file.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("invalid color mode `%s`, expected auto, always or never", mode)
+}
+
+// Painter styles rendered output with ANSI escape sequences. It only reads its
+// theme, so it may be shared by goroutines.
+type Painter struct {
+	styles map[string]string // Escape sequences of the roles of the theme
+}
+
+// NewPainter creates a painter for a theme. Roles missing from the theme keep
+// the style of the default theme.
+func NewPainter(theme Theme) (*Painter, error) {
+	styles := make(map[string]string, len(DefaultTheme))
+	for role, style := range DefaultTheme {
+		if custom, ok := theme[role]; ok {
+			style = custom
+		}
+		sequence, err := ParseStyle(style)
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %w", role, err)
+		}
+		styles[role] = sequence
+	}
+	return &Painter{styles: styles}, nil
+}
+
+// Style wraps text in the escape sequence of a role or style
+func (p *Painter) Style(style, text string) string {
+	sequence, ok := p.styles[style]
+	if !ok {
+		var err error
+		if sequence, err = ParseStyle(style); err != nil {
+			return text
+		}
+	}
+	if text == "" {
+		return text
+	}
+	return sequence + text + reset
+}
+
+// Field styles the value of a field. Without a modifier the value is styled
+// according to the field: levels by severity, timestamps dimmed and JSON
+// highlighted, including JSON decoded from fields like base64+json. Fields of
+// the level type and fields named like one of models.LevelFields hold levels.
+func (p *Painter) Field(field *models.Field, modifier, value string) string {
+	if modifier != "" && modifier != Auto {
+		return p.Style(modifier, value)
+	}
+
+	// Levels are recognized by the field, or by value with Auto
+	if severity := Severity(value); severity != "" && (modifier == Auto || isLevelField(field)) {
+		return p.Style(severity, value)
+	}
+	if field == nil {
+		return value
+	}
+	switch {
+	case field.Type == models.Timestamp:
+		return p.Style("timestamp", value)
+	case field.Type == models.JSON:
+		return p.JSON(value)
+	case field.Type.Decodes() && field.Decoded == models.JSON && json.Valid([]byte(value)):
+		// Values that could not be decoded are left as they are
+		return p.JSON(value)
+	}
+	return value
+}
+
+// Text styles literal text of a target template, replacing markup tags like
+// {red} or {bold+red} with their escape sequence and {/} with a reset
+func (p *Painter) Text(text string) string {
+	return markupRegex.ReplaceAllStringFunc(text, func(tag string) string {
+		name := tag[1 : len(tag)-1]
+		if name == "/" {
+			return reset
+		}
+		if sequence, ok := p.styles[name]; ok {
+			return sequence
+		}
+		if sequence, err := ParseStyle(name); err == nil {
+			return sequence
+		}
+		return tag
+	})
+}
+
+// StripMarkup removes the markup tags from literal text of a target template
+func StripMarkup(text string) string {
+	return markupRegex.ReplaceAllStringFunc(text, func(tag string) string {
+		name := tag[1 : len(tag)-1]
+		if name == "/" || isMarkup(name) {
+			return ""
+		}
+		return tag
+	})
+}
+
+// JSON highlights the keys, strings, numbers and literals of JSON text
+func (p *Painter) JSON(text string) string {
+	var builder strings.Builder
+	for i := 0; i < len(text); {
+		char := text[i]
+		switch {
+		case char == '"':
+			end := stringEnd(text, i)
+			role := "json.string"
+			if rest := strings.TrimLeft(text[end:], " \t\r\n"); strings.HasPrefix(rest, ":") {
+				role = "json.key"
+			}
+			builder.WriteString(p.Style(role, text[i:end]))
+			i = end
+		case char == '-' || (char >= '0' && char <= '9'):
+			end := i + 1
+			for end < len(text) && strings.IndexByte("0123456789.eE+-", text[end]) >= 0 {
+				end++
+			}
+			builder.WriteString(p.Style("json.number", text[i:end]))
+			i = end
+		case strings.HasPrefix(text[i:], "true"), strings.HasPrefix(text[i:], "null"):
+			builder.WriteString(p.Style("json.literal", text[i:i+4]))
+			i += 4
+		case strings.HasPrefix(text[i:], "false"):
+			builder.WriteString(p.Style("json.literal", text[i:i+5]))
+			i += 5
+		default:
+			builder.WriteByte(char)
+			i++
+		}
+	}
+	return builder.String()
+}
+
+// stringEnd returns the offset after the JSON string starting at start
+func stringEnd(text string, start int) int {
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+// isLevelField reports whether a field holds levels
+func isLevelField(field *models.Field) bool {
+	return field != nil && (field.Type == models.Level || slices.Contains(models.LevelFields, strings.ToLower(field.Name)))
+}
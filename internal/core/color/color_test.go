@@ -0,0 +1,134 @@
+package color
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		style   string
+		want    string
+		wantErr bool
+	}{
+		{style: "red", want: "\x1b[31m"},
+		{style: "bold red", want: "\x1b[1;31m"},
+		{style: "Bold+Red", want: "\x1b[1;31m"},
+		{style: "", wantErr: true},
+		{style: "pink", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			got, err := ParseStyle(tt.style)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStyle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStyle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPainter_Field(t *testing.T) {
+	painter, err := NewPainter(Theme{"level.warn": "bold yellow"})
+	if err != nil {
+		t.Fatalf("NewPainter() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		field    *models.Field
+		modifier string
+		value    string
+		want     string
+	}{
+		{"level by field name", &models.Field{Name: "level", Type: models.String}, "", "warn", "\x1b[1;33mwarn\x1b[0m"},
+		{"fatal level", &models.Field{Name: "severity", Type: models.String}, "", "CRITICAL", "\x1b[1;31mCRITICAL\x1b[0m"},
+		{"unknown level", &models.Field{Name: "level", Type: models.String}, "", "chatty", "chatty"},
+		{"level by value", &models.Field{Name: "status", Type: models.String}, Auto, "ERROR", "\x1b[31mERROR\x1b[0m"},
+		{"level value of other field", &models.Field{Name: "status", Type: models.String}, "", "ERROR", "ERROR"},
+		{"timestamp dimmed", &models.Field{Name: "time", Type: models.Timestamp}, "", "10:00", "\x1b[2m10:00\x1b[0m"},
+		{"explicit style", &models.Field{Name: "message", Type: models.String}, "bold+red", "boom", "\x1b[1;31mboom\x1b[0m"},
+		{"theme role", &models.Field{Name: "message", Type: models.String}, "json.key", "boom", "\x1b[36mboom\x1b[0m"},
+		{"empty value", &models.Field{Name: "message", Type: models.String}, "red", "", ""},
+		{"decoded JSON", &models.Field{Name: "body", Type: models.Base64, Decoded: models.JSON}, "", "[1]", "[\x1b[33m1\x1b[0m]"},
+		{"undecoded JSON", &models.Field{Name: "body", Type: models.Base64, Decoded: models.JSON}, "", "WzFd!", "WzFd!"},
+		{"missing field", nil, "", "value", "value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := painter.Field(tt.field, tt.modifier, tt.value); got != tt.want {
+				t.Errorf("Painter.Field() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPainter_JSON(t *testing.T) {
+	painter, err := NewPainter(nil)
+	if err != nil {
+		t.Fatalf("NewPainter() error = %v", err)
+	}
+
+	got := painter.JSON(`{"a": "x\"y", "b": [-1.5e3, true, null]}`)
+	want := "{\x1b[36m\"a\"\x1b[0m: \x1b[32m\"x\\\"y\"\x1b[0m, \x1b[36m\"b\"\x1b[0m: [\x1b[33m-1.5e3\x1b[0m, \x1b[35mtrue\x1b[0m, \x1b[35mnull\x1b[0m]}"
+	if got != want {
+		t.Errorf("Painter.JSON() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkup(t *testing.T) {
+	painter, err := NewPainter(nil)
+	if err != nil {
+		t.Fatalf("NewPainter() error = %v", err)
+	}
+
+	text := "{bold+red}error{/} in {user id} {level.warn}!{/}"
+	if got, want := painter.Text(text), "\x1b[1;31merror\x1b[0m in {user id} \x1b[33m!\x1b[0m"; got != want {
+		t.Errorf("Painter.Text() = %q, want %q", got, want)
+	}
+	if got, want := StripMarkup(text), "error in {user id} !"; got != want {
+		t.Errorf("StripMarkup() = %q, want %q", got, want)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	// A regular file is not a terminal
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer file.Close()
+
+	tests := []struct {
+		mode    string
+		noColor string
+		want    bool
+		wantErr bool
+	}{
+		{mode: ModeAlways, noColor: "1", want: true},
+		{mode: ModeNever, want: false},
+		{mode: ModeAuto, want: false},
+		{mode: ModeAuto, noColor: "1", want: false},
+		{mode: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			got, err := Enabled(tt.mode, file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Enabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
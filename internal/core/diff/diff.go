@@ -0,0 +1,254 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines shown around changes
+const DefaultContext = 3
+
+// Kinds of edits
+const (
+	Equal   = ' ' // Item is in both sequences
+	Removed = '-' // Item is only in the first sequence
+	Added   = '+' // Item is only in the second sequence
+)
+
+// Edit is a step turning the first sequence into the second
+type Edit struct {
+	Kind byte
+	A    int // Index in the first sequence, -1 for added items
+	B    int // Index in the second sequence, -1 for removed items
+}
+
+// Align returns the shortest list of edits turning a into b, using Myers'
+// algorithm. Removals come before additions in each changed block.
+func Align(a, b []string) []Edit {
+	// Items shared at the start and end need no search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit{Kind: Equal, A: i, B: i})
+	}
+	for _, edit := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		if edit.A >= 0 {
+			edit.A += prefix
+		}
+		if edit.B >= 0 {
+			edit.B += prefix
+		}
+		edits = append(edits, edit)
+	}
+	for i := 0; i < suffix; i++ {
+		edits = append(edits, Edit{Kind: Equal, A: len(a) - suffix + i, B: len(b) - suffix + i})
+	}
+	return edits
+}
+
+// maxCost bounds the edit search on very different inputs, whose middle part is
+// then replaced as a whole
+const maxCost = 1024
+
+// myers finds the shortest edit script with the greedy algorithm from
+// "An O(ND) Difference Algorithm and Its Variations", keeping the furthest
+// reaching paths of every step to trace the edits back
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	// trace[d] holds the furthest x of diagonals -d-1 to d+1 after step d
+	trace := [][]int{}
+	at := func(d, k int) int { return trace[d][k+d+1] }
+
+	found := false
+	for d := 0; d <= n+m && d <= maxCost && !found; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Move down, adding from b
+			} else {
+				x = v[offset+k-1] + 1 // Move right, removing from a
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+	}
+	if !found {
+		return replace(a, b)
+	}
+
+	// Walk the paths back from the end
+	edits := []Edit{}
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+		prevX, prevK := 0, 0
+		if d > 0 {
+			if k == -d || (k != d && at(d-1, k-1) < at(d-1, k+1)) {
+				prevK = k + 1
+			} else {
+				prevK = k - 1
+			}
+			prevX = at(d-1, prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			edits = append(edits, Edit{Kind: Equal, A: x, B: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				edits = append(edits, Edit{Kind: Added, A: -1, B: y})
+			} else {
+				x--
+				edits = append(edits, Edit{Kind: Removed, A: x, B: -1})
+			}
+		}
+	}
+
+	// Reverse into order, then put removals before additions in each block
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return groupBlocks(edits)
+}
+
+// replace returns edits removing all of a and adding all of b
+func replace(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	for i := range a {
+		edits = append(edits, Edit{Kind: Removed, A: i, B: -1})
+	}
+	for i := range b {
+		edits = append(edits, Edit{Kind: Added, A: -1, B: i})
+	}
+	return edits
+}
+
+// groupBlocks reorders each run of removals and additions so removals come first
+func groupBlocks(edits []Edit) []Edit {
+	for start := 0; start < len(edits); {
+		if edits[start].Kind == Equal {
+			start++
+			continue
+		}
+		end := start
+		for end < len(edits) && edits[end].Kind != Equal {
+			end++
+		}
+		block := append([]Edit(nil), edits[start:end]...)
+		i := start
+		for _, edit := range block {
+			if edit.Kind == Removed {
+				edits[i] = edit
+				i++
+			}
+		}
+		for _, edit := range block {
+			if edit.Kind == Added {
+				edits[i] = edit
+				i++
+			}
+		}
+		start = end
+	}
+	return edits
+}
+
+// Hunk is a range of edits shown together with their context
+type Hunk struct {
+	Start, End int // Range of the hunk in the edits
+}
+
+// Hunks groups the edits that are not Equal into hunks with up to context unchanged
+// edits around them. Changes closer than twice the context share a hunk.
+func Hunks(edits []Edit, context int) []Hunk {
+	hunks := []Hunk{}
+	for i := 0; i < len(edits); i++ {
+		if edits[i].Kind == Equal {
+			continue
+		}
+
+		start := max(i-context, 0)
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].End {
+			start = hunks[len(hunks)-1].Start
+			hunks = hunks[:len(hunks)-1]
+		}
+
+		// Extend over the change and the context after it
+		end := i
+		for end < len(edits) && edits[end].Kind != Equal {
+			end++
+		}
+		i = end - 1
+		hunks = append(hunks, Hunk{Start: start, End: min(end+context, len(edits))})
+	}
+	return hunks
+}
+
+// Unified returns the differences of two lists of lines in unified diff format,
+// empty if they are equal
+func Unified(a, b []string, nameA, nameB string, context int) string {
+	edits := Align(a, b)
+	hunks := Hunks(edits, context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	out := strings.Builder{}
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+	for _, hunk := range hunks {
+		out.WriteString(Header(edits[hunk.Start:hunk.End], func(i int) int { return i + 1 }, func(i int) int { return i + 1 }))
+		for _, edit := range edits[hunk.Start:hunk.End] {
+			line := ""
+			if edit.Kind == Added {
+				line = b[edit.B]
+			} else {
+				line = a[edit.A]
+			}
+			fmt.Fprintf(&out, "%c%s\n", edit.Kind, line)
+		}
+	}
+	return out.String()
+}
+
+// Header returns the "@@ -start,count +start,count @@" line of a hunk, numbering
+// items of each side with the given functions
+func Header(edits []Edit, lineA, lineB func(int) int) string {
+	startA, countA, startB, countB := 0, 0, 0, 0
+	for _, edit := range edits {
+		if edit.A >= 0 {
+			if countA == 0 {
+				startA = lineA(edit.A)
+			}
+			countA++
+		}
+		if edit.B >= 0 {
+			if countB == 0 {
+				startB = lineB(edit.B)
+			}
+			countB++
+		}
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", startA, countA, startB, countB)
+}
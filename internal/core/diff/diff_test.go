@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// script renders edits as the kinds and items they refer to, like "=a -b +c"
+func script(edits []Edit, a, b []string) string {
+	parts := []string{}
+	for _, edit := range edits {
+		switch edit.Kind {
+		case Equal:
+			parts = append(parts, "="+a[edit.A])
+		case Removed:
+			parts = append(parts, "-"+a[edit.A])
+		case Added:
+			parts = append(parts, "+"+b[edit.B])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{name: "Equal", a: "a b c", b: "a b c", want: "=a =b =c"},
+		{name: "Empty", a: "", b: "", want: ""},
+		{name: "All added", a: "", b: "a b", want: "+a +b"},
+		{name: "All removed", a: "a b", b: "", want: "-a -b"},
+		{name: "Changed middle", a: "a b c", b: "a x c", want: "=a -b +x =c"},
+		{name: "Inserted", a: "a c", b: "a b c", want: "=a +b =c"},
+		{name: "Removed", a: "a b c d", b: "a d", want: "=a -b -c =d"},
+		{name: "Classic", a: "a b c a b b a", b: "c b a b a c", want: "-a -b =c +b =a =b -b =a +c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+			if got := script(Align(a, b), a, b); got != tt.want {
+				t.Errorf("Align() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlign_Replace(t *testing.T) {
+	// Inputs differing beyond the search bound are replaced as a whole
+	a, b := make([]string, maxCost), make([]string, maxCost)
+	for i := range a {
+		a[i], b[i] = "a", "b"
+	}
+
+	edits := Align(a, b)
+	if len(edits) != 2*maxCost || edits[0].Kind != Removed || edits[len(edits)-1].Kind != Added {
+		t.Errorf("Align() returned %d edits, want all removed then all added", len(edits))
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	b := []string{"1", "2", "three", "4", "5", "6", "7", "8", "9", "10", "11"}
+
+	want := `--- expected
++++ actual
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -10,1 +10,2 @@
+ 10
++11
+`
+	if got := Unified(a, b, "expected", "actual", 1); got != want {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+
+	if got := Unified(a, a, "expected", "actual", 1); got != "" {
+		t.Errorf("Unified() = %q, want empty for equal lines", got)
+	}
+}
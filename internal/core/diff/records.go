@@ -0,0 +1,227 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// Changed is the kind of record edits pairing similar removed and added records
+const Changed = '~'
+
+// FieldChange is a field whose value differs between two records
+type FieldChange struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// RecordEdit is a step turning the first list of records into the second
+type RecordEdit struct {
+	Kind   byte           // Equal, Removed, Added or Changed
+	A      *models.Record // Record of the first list, nil for added records
+	B      *models.Record // Record of the second list, nil for removed records
+	Fields []FieldChange  // Differing fields of changed records
+}
+
+// Records aligns two lists of records by their field values, skipping the ignored
+// fields and their sub-fields. Records that don't match the template are compared
+// by their raw text. Removed and added records with the same fields and mostly the
+// same words in their values are paired as changed records.
+func Records(a, b []*models.Record, ignore []string) []RecordEdit {
+	keysA, keysB := make([]string, len(a)), make([]string, len(b))
+	for i, record := range a {
+		keysA[i] = key(record, ignore)
+	}
+	for i, record := range b {
+		keysB[i] = key(record, ignore)
+	}
+
+	edits := Align(keysA, keysB)
+	result := make([]RecordEdit, 0, len(edits))
+	for start := 0; start < len(edits); {
+		if edits[start].Kind == Equal {
+			result = append(result, RecordEdit{Kind: Equal, A: a[edits[start].A], B: b[edits[start].B]})
+			start++
+			continue
+		}
+
+		// Pair the removals and additions of a block in order while they are similar
+		end := start
+		for end < len(edits) && edits[end].Kind != Equal {
+			end++
+		}
+		removed, added := []*models.Record{}, []*models.Record{}
+		for _, edit := range edits[start:end] {
+			if edit.Kind == Removed {
+				removed = append(removed, a[edit.A])
+			} else {
+				added = append(added, b[edit.B])
+			}
+		}
+
+		paired := 0
+		for paired < len(removed) && paired < len(added) {
+			fields, similar := compare(removed[paired], added[paired], ignore)
+			if !similar {
+				break
+			}
+			result = append(result, RecordEdit{Kind: Changed, A: removed[paired], B: added[paired], Fields: fields})
+			paired++
+		}
+		for _, record := range removed[paired:] {
+			result = append(result, RecordEdit{Kind: Removed, A: record})
+		}
+		for _, record := range added[paired:] {
+			result = append(result, RecordEdit{Kind: Added, B: record})
+		}
+		start = end
+	}
+	return result
+}
+
+// UnifiedRecords returns the differences of aligned records in unified diff format,
+// showing the raw lines and the changed fields of changed records. It is empty if
+// all records are equal.
+func UnifiedRecords(edits []RecordEdit, nameA, nameB string, context int) string {
+	// Hunks are found on the plain edits, changed records counting as a change
+	plain := make([]Edit, len(edits))
+	for i, edit := range edits {
+		plain[i] = Edit{Kind: edit.Kind, A: -1, B: -1}
+		if edit.A != nil {
+			plain[i].A = i
+		}
+		if edit.B != nil {
+			plain[i].B = i
+		}
+	}
+	hunks := Hunks(plain, context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	lineA := func(i int) int { return edits[i].A.Line }
+	lineB := func(i int) int { return edits[i].B.Line }
+
+	out := strings.Builder{}
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+	for _, hunk := range hunks {
+		out.WriteString(Header(plain[hunk.Start:hunk.End], lineA, lineB))
+		for _, edit := range edits[hunk.Start:hunk.End] {
+			switch edit.Kind {
+			case Equal:
+				fmt.Fprintf(&out, " %s\n", edit.A.Raw)
+			case Removed:
+				fmt.Fprintf(&out, "-%s\n", edit.A.Raw)
+			case Added:
+				fmt.Fprintf(&out, "+%s\n", edit.B.Raw)
+			case Changed:
+				fmt.Fprintf(&out, "-%s\n+%s\n", edit.A.Raw, edit.B.Raw)
+				for _, field := range edit.Fields {
+					fmt.Fprintf(&out, "~   %s: %q -> %q\n", field.Name, field.Old, field.New)
+				}
+			}
+		}
+	}
+	return out.String()
+}
+
+// key identifies a record by its field values without the ignored fields
+func key(record *models.Record, ignore []string) string {
+	if !record.Matched {
+		return "\x00" + record.Raw
+	}
+
+	out := strings.Builder{}
+	for _, name := range fields(record, ignore) {
+		value, _ := record.Value(name)
+		out.WriteString(name)
+		out.WriteByte('\x1f')
+		out.WriteString(value)
+		out.WriteByte('\x1e')
+	}
+	return out.String()
+}
+
+// compare returns the fields whose values differ between two matched records, and
+// whether the records are similar: same fields with at least half the words of
+// their values in common
+func compare(a, b *models.Record, ignore []string) ([]FieldChange, bool) {
+	if !a.Matched || !b.Matched {
+		return nil, false
+	}
+
+	names := fields(b, ignore)
+	if len(names) != len(fields(a, ignore)) {
+		return nil, false
+	}
+
+	changes := []FieldChange{}
+	tokensA, tokensB := []string{}, []string{}
+	for _, name := range names {
+		old, ok := a.Value(name)
+		if !ok {
+			return nil, false
+		}
+		value, _ := b.Value(name)
+		if value != old {
+			changes = append(changes, FieldChange{Name: name, Old: old, New: value})
+		}
+		tokensA = append(tokensA, strings.Fields(old)...)
+		tokensB = append(tokensB, strings.Fields(value)...)
+	}
+	return changes, 2*shared(tokensA, tokensB) >= max(len(tokensA), len(tokensB))
+}
+
+// shared returns the number of tokens two lists have in common
+func shared(a, b []string) int {
+	counts := map[string]int{}
+	for _, token := range a {
+		counts[token]++
+	}
+	count := 0
+	for _, token := range b {
+		if counts[token] > 0 {
+			counts[token]--
+			count++
+		}
+	}
+	return count
+}
+
+// fields returns the names of the compared fields of a record: fields that are not
+// ignored, leaving out structured fields whose sub-fields are compared instead
+func fields(record *models.Record, ignore []string) []string {
+	names := []string{}
+	for _, name := range record.Names() {
+		if ignored(name, ignore) {
+			continue
+		}
+		if field, _ := record.Field(name); field.HasSubFields() && hasSubFields(record, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// hasSubFields checks whether a record holds sub-fields of a field
+func hasSubFields(record *models.Record, name string) bool {
+	for _, other := range record.Names() {
+		if strings.HasPrefix(other, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ignored checks whether a field or the field it belongs to is ignored
+func ignored(name string, ignore []string) bool {
+	for _, field := range ignore {
+		if name == field || strings.HasPrefix(name, field+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// newRecords parses lines of "time level message" into records, lines starting
+// with "!" don't match
+func newRecords(lines ...string) []*models.Record {
+	records := make([]*models.Record, len(lines))
+	for i, line := range lines {
+		records[i] = models.NewRecord(i+1, line)
+		if strings.HasPrefix(line, "!") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 3)
+		records[i].Set(&models.Field{Name: "time", Type: models.String}, parts[0])
+		records[i].Set(&models.Field{Name: "level", Type: models.String}, parts[1])
+		records[i].Set(&models.Field{Name: "message", Type: models.String}, parts[2])
+		records[i].Matched = true
+	}
+	return records
+}
+
+func TestRecords(t *testing.T) {
+	a := newRecords(
+		"10:00 INFO start suite",
+		"10:01 INFO test checkout passed",
+		"10:02 INFO cache cleared",
+		"! stray line",
+		"10:03 INFO end suite",
+	)
+	b := newRecords(
+		"11:00 INFO start suite",
+		"11:01 ERROR test checkout failed",
+		"11:02 INFO test profile passed",
+		"! stray line",
+		"11:03 INFO end suite",
+	)
+
+	edits := Records(a, b, []string{"time"})
+
+	kinds := ""
+	for _, edit := range edits {
+		kinds += string(edit.Kind)
+	}
+	if kinds != " ~-+  " {
+		t.Fatalf("Records() kinds = %q, want %q", kinds, " ~-+  ")
+	}
+
+	want := []FieldChange{
+		{Name: "level", Old: "INFO", New: "ERROR"},
+		{Name: "message", Old: "test checkout passed", New: "test checkout failed"},
+	}
+	if !reflect.DeepEqual(edits[1].Fields, want) {
+		t.Errorf("Records() changed fields = %v, want %v", edits[1].Fields, want)
+	}
+
+	// Without ignoring the time every record differs
+	for _, edit := range Records(a, b, nil) {
+		if edit.Kind == Equal && edit.A.Matched {
+			t.Errorf("Records() = equal %q, want time to be compared", edit.A.Raw)
+		}
+	}
+}
+
+func TestRecords_SubFields(t *testing.T) {
+	newRecord := func(line int, time, code string) *models.Record {
+		record := models.NewRecord(line, time+" "+code)
+		record.Set(&models.Field{Name: "details", Type: models.JSON}, `{"time":"`+time+`","code":"`+code+`"}`)
+		record.Set(&models.Field{Name: "details.time", Type: models.String}, time)
+		record.Set(&models.Field{Name: "details.code", Type: models.String}, code)
+		record.Matched = true
+		return record
+	}
+
+	// Ignoring a sub-field also leaves out the structured field holding it
+	a := []*models.Record{newRecord(1, "10:00", "E1")}
+	b := []*models.Record{newRecord(1, "11:00", "E1")}
+	if edits := Records(a, b, []string{"details.time"}); edits[0].Kind != Equal {
+		t.Errorf("Records() kind = %q, want equal", edits[0].Kind)
+	}
+}
+
+func TestUnifiedRecords(t *testing.T) {
+	a := newRecords("10:00 INFO start", "10:01 INFO test checkout passed", "10:02 INFO end")
+	b := newRecords("11:00 INFO start", "11:01 ERROR test checkout failed", "11:02 INFO end", "11:03 INFO extra line here")
+
+	want := `--- a.log
++++ b.log
+@@ -1,3 +1,4 @@
+ 10:00 INFO start
+-10:01 INFO test checkout passed
++11:01 ERROR test checkout failed
+~   level: "INFO" -> "ERROR"
+~   message: "test checkout passed" -> "test checkout failed"
+ 10:02 INFO end
++11:03 INFO extra line here
+`
+	if got := UnifiedRecords(Records(a, b, []string{"time"}), "a.log", "b.log", DefaultContext); got != want {
+		t.Errorf("UnifiedRecords() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package fixtures
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/diff"
+	"github.com/gitKashish/golog/internal/core/formatter"
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/pkg/fileutil"
+)
+
+// GoldenExt is the extension of files holding the expected output of an input file
+const GoldenExt = ".golden"
+
+// InputExt is the extension of input files compared with golden files
+const InputExt = ".log"
+
+// Result is the outcome of a template test
+type Result struct {
+	Name     string
+	Failures []string // Descriptions of unexpected field values
+	Diff     string   // Unified diff of the expected and actual output, empty if equal
+}
+
+// Passed checks whether the output and fields were as expected
+func (r Result) Passed() bool {
+	return r.Diff == "" && len(r.Failures) == 0
+}
+
+// Run renders the input of a template test and compares it with the expected
+// output and field values
+func Run(p *parser.TemplateParser, test models.TemplateTest) (Result, error) {
+	result := Result{Name: test.Name}
+	records, kept, err := render(p, splitLines(test.Input))
+	if err != nil {
+		return result, err
+	}
+
+	result.Diff = diff.Unified(splitLines(test.Output), outputs(kept), "expected", "actual", diff.DefaultContext)
+
+	// Fields are checked in a stable order so failures read the same every run
+	names := make([]string, 0, len(test.Fields))
+	for name := range test.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		want := test.Fields[name]
+		if len(records) == 0 || !records[0].Matched {
+			result.Failures = append(result.Failures, fmt.Sprintf("field `%s`: input doesn't match the template, want %q", name, want))
+			continue
+		}
+		got, ok := records[0].Value(name)
+		switch {
+		case !ok:
+			result.Failures = append(result.Failures, fmt.Sprintf("field `%s`: missing, want %q", name, want))
+		case got != want:
+			result.Failures = append(result.Failures, fmt.Sprintf("field `%s`: got %q, want %q", name, got, want))
+		}
+	}
+	return result, nil
+}
+
+// RunGolden renders an input file read with the given options and compares it
+// with its golden file. With update, the golden file is written with the actual
+// output instead.
+func RunGolden(p *parser.TemplateParser, options fileutil.ReadOptions, inputPath string, update bool) (Result, error) {
+	result := Result{Name: inputPath}
+	fileUtil := &fileutil.FileUtil{Options: options}
+
+	lines, err := fileUtil.ReadLines(inputPath)
+	if err != nil {
+		return result, err
+	}
+	_, kept, err := render(p, lines)
+	if err != nil {
+		return result, err
+	}
+	actual := outputs(kept)
+
+	goldenPath := inputPath + GoldenExt
+	if update {
+		// Golden files hold the output as show prints it
+		content := strings.Join(actual, "\n") + "\n"
+		if len(actual) == 0 {
+			content = ""
+		}
+		return result, os.WriteFile(goldenPath, []byte(content), 0644)
+	}
+
+	if !fileUtil.FileExists(goldenPath) {
+		result.Failures = append(result.Failures, fmt.Sprintf("golden file %s is missing, run with --update to create it", goldenPath))
+		return result, nil
+	}
+	expected, err := fileUtil.ReadLines(goldenPath)
+	if err != nil {
+		return result, err
+	}
+
+	result.Diff = diff.Unified(expected, actual, goldenPath, "actual", diff.DefaultContext)
+	return result, nil
+}
+
+// GoldenInputs returns the input files of a directory, the files with the
+// InputExt extension, so notes or editor files beside them are left alone. It
+// returns nothing if the directory doesn't exist.
+func GoldenInputs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != InputExt {
+			continue
+		}
+		inputs = append(inputs, filepath.Join(dir, entry.Name()))
+	}
+	return inputs, nil
+}
+
+// render parses lines and applies the mismatch policy of the template like show
+// does, returning the parsed records and the records it outputs. Rejected
+// records are left out of the output.
+func render(p *parser.TemplateParser, lines []string) ([]*models.Record, []*models.Record, error) {
+	records, err := p.ParseRecords(lines)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policy := formatter.NewMismatchPolicy(p.Literals())
+	policy.Continue = p.Continue
+	kept, err := policy.Apply(records, func(*models.Record) error { return nil })
+	if err != nil {
+		return nil, nil, err
+	}
+	return records, kept, nil
+}
+
+// outputs returns the rendered output of records, one line per record
+func outputs(records []*models.Record) []string {
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		lines = append(lines, strings.Split(record.Output, "\n")...)
+	}
+	return lines
+}
+
+// splitLines splits text into lines, ignoring the line break YAML block
+// scalars end with
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(text, "\n")
+}
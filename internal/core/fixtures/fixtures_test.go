@@ -0,0 +1,172 @@
+package fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/pkg/fileutil"
+)
+
+// newParser creates a parser for "time level message" lines
+func newParser(t *testing.T) *parser.TemplateParser {
+	t.Helper()
+	p := parser.NewTemplateParser()
+	if err := p.SetTemplate("@time-string@ @level-string@ @message-string@", "[@level@] @message@"); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+	return p
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		test         models.TemplateTest
+		wantDiff     bool
+		wantFailures []string
+	}{
+		{
+			name: "Passing",
+			test: models.TemplateTest{
+				Input:  "10:00 INFO started\n10:01 WARN disk low\n",
+				Output: "[INFO] started\n[WARN] disk low\n",
+				Fields: map[string]string{"level": "INFO", "message": "started"},
+			},
+		},
+		{
+			name:     "Different output",
+			test:     models.TemplateTest{Input: "10:00 INFO started", Output: "[INFO] stopped"},
+			wantDiff: true,
+		},
+		{
+			name: "Different fields",
+			test: models.TemplateTest{
+				Input:  "10:00 INFO started",
+				Output: "[INFO] started",
+				Fields: map[string]string{"level": "WARN", "host": "web01"},
+			},
+			wantFailures: []string{
+				"field `host`: missing, want \"web01\"",
+				"field `level`: got \"INFO\", want \"WARN\"",
+			},
+		},
+		{
+			name: "Not matching",
+			test: models.TemplateTest{
+				Input:  "started",
+				Output: "started",
+				Fields: map[string]string{"level": "INFO"},
+			},
+			wantFailures: []string{"field `level`: input doesn't match the template, want \"INFO\""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(newParser(t), tt.test)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if (result.Diff != "") != tt.wantDiff {
+				t.Errorf("Run() diff = %q, want diff %v", result.Diff, tt.wantDiff)
+			}
+			if strings.Join(result.Failures, "|") != strings.Join(tt.wantFailures, "|") {
+				t.Errorf("Run() failures = %q, want %q", result.Failures, tt.wantFailures)
+			}
+			if result.Passed() != (!tt.wantDiff && len(tt.wantFailures) == 0) {
+				t.Errorf("Passed() = %v", result.Passed())
+			}
+		})
+	}
+}
+
+func TestRun_OnMismatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		onMismatch string
+		output     string
+	}{
+		{name: "Drop", onMismatch: models.MismatchDrop, output: "[INFO] started\n[ERROR] failed\n"},
+		{name: "Reject", onMismatch: models.MismatchReject, output: "[INFO] started\n[ERROR] failed\n"},
+		{name: "Append to previous", onMismatch: models.MismatchAppend, output: "[INFO] started\ndetails\n[ERROR] failed\n"},
+		{name: "Mark", onMismatch: models.MismatchMark, output: "[INFO] started\n" + models.DefaultMarker + "details\n[ERROR] failed\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewTemplateParser()
+			literals := models.TemplateLiterals{
+				Source:     "@time-string@ @level-string@ @message-string@",
+				Target:     "[@level@] @message@",
+				OnMismatch: tt.onMismatch,
+			}
+			if err := p.SetLiterals(literals); err != nil {
+				t.Fatalf("Failed to set template: %v", err)
+			}
+
+			// The output is checked as show prints it
+			test := models.TemplateTest{
+				Input:  "10:00 INFO started\ndetails\n10:01 ERROR failed\n",
+				Output: tt.output,
+				Fields: map[string]string{"level": "INFO"},
+			}
+			result, err := Run(p, test)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !result.Passed() {
+				t.Errorf("Run() diff = %q, failures = %q, want passed", result.Diff, result.Failures)
+			}
+		})
+	}
+}
+
+func TestRunGolden(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(input, []byte("10:00 INFO started\nnot matching\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	p := newParser(t)
+
+	// A missing golden file fails until it is written
+	result, err := RunGolden(p, fileutil.ReadOptions{}, input, false)
+	if err != nil || result.Passed() {
+		t.Fatalf("RunGolden() = %v, %v, want missing golden file failure", result, err)
+	}
+	if _, err := RunGolden(p, fileutil.ReadOptions{}, input, true); err != nil {
+		t.Fatalf("RunGolden() update error = %v", err)
+	}
+	data, err := os.ReadFile(input + GoldenExt)
+	if err != nil || string(data) != "[INFO] started\nnot matching\n" {
+		t.Fatalf("Golden file = %q, %v", data, err)
+	}
+
+	result, err = RunGolden(p, fileutil.ReadOptions{}, input, false)
+	if err != nil || !result.Passed() {
+		t.Errorf("RunGolden() = %v, %v, want passed", result, err)
+	}
+
+	// Changed output is shown as a diff against the golden file
+	if err := os.WriteFile(input, []byte("10:00 WARN started\nnot matching\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input: %v", err)
+	}
+	result, err = RunGolden(p, fileutil.ReadOptions{}, input, false)
+	if err != nil || !strings.Contains(result.Diff, "-[INFO] started\n+[WARN] started\n") {
+		t.Errorf("RunGolden() diff = %q, %v", result.Diff, err)
+	}
+
+	// Only .log files are inputs
+	for _, name := range []string{"README.md", "app.log~"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("notes\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	inputs, err := GoldenInputs(dir)
+	if err != nil || len(inputs) != 1 || inputs[0] != input {
+		t.Errorf("GoldenInputs() = %v, %v, want [%s]", inputs, err, input)
+	}
+}
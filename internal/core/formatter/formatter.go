@@ -1,7 +1,9 @@
 package formatter
 
 import (
+	"github.com/gitKashish/golog/internal/core/models"
 	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/pkg/fileutil"
 )
 
 // LogFormatter defines the interface for formatting logs
@@ -16,7 +18,8 @@ type LogFormatter interface {
 
 // TemplateFormatter implements the LogFormatter interface
 type TemplateFormatter struct {
-	parser parser.LogParser
+	parser      parser.LogParser
+	readOptions fileutil.ReadOptions // How lines of files are read
 }
 
 // NewTemplateFormatter creates a new TemplateFormatter
@@ -40,7 +43,28 @@ func (f *TemplateFormatter) FormatLogs(logs []string) []string {
 	return formattedLogs
 }
 
+// FormatRecords formats log lines and returns the parsed records.
+// Parsers that do not expose field values produce records holding only the output.
+func (f *TemplateFormatter) FormatRecords(logs []string) ([]*models.Record, error) {
+	if recordParser, ok := f.parser.(parser.RecordParser); ok {
+		return recordParser.ParseRecords(logs)
+	}
+
+	records := make([]*models.Record, len(logs))
+	for i, log := range logs {
+		records[i] = models.NewRecord(i+1, log)
+		records[i].Output = f.FormatLog(log)
+		records[i].Matched = true
+	}
+	return records, nil
+}
+
 // SetParser sets the parser to use for formatting
 func (f *TemplateFormatter) SetParser(parser parser.LogParser) {
 	f.parser = parser
 }
+
+// SetReadOptions sets how lines of files are read by FormatFile
+func (f *TemplateFormatter) SetReadOptions(options fileutil.ReadOptions) {
+	f.readOptions = options
+}
@@ -140,3 +140,24 @@ func TestTemplateFormatter_WithRealParser_Simple(t *testing.T) {
 		t.Errorf("FormatLog() = %v, does not contain expected content", result)
 	}
 }
+
+func TestTemplateFormatter_FormatRecords(t *testing.T) {
+	// Create a formatter with a parser that does not expose field values
+	mockParser := &MockParser{
+		parseFunc: func(sourceLog string) string {
+			return "[FORMATTED] " + sourceLog
+		},
+	}
+	formatter := NewTemplateFormatter(mockParser)
+
+	records, err := formatter.FormatRecords([]string{"Log message 1", "Log message 2"})
+	if err != nil {
+		t.Fatalf("FormatRecords() error = %v", err)
+	}
+
+	for i, record := range records {
+		if record.Line != i+1 || record.Output != "[FORMATTED] "+record.Raw {
+			t.Errorf("FormatRecords()[%d] = line %d %q, want formatted output", i, record.Line, record.Output)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// FilterSeverity keeps the records at or above a minimum severity. Records
+// without a level, like lines not matching the template, are kept, and records
+// with a level that isn't recognized are taken as below the minimum.
+func FilterSeverity(records []*models.Record, minimum models.Severity) []*models.Record {
+	kept := make([]*models.Record, 0, len(records))
+	for _, record := range records {
+		if _, hasLevel := record.Level(); hasLevel {
+			if severity, ok := record.Severity(); !ok || severity < minimum {
+				continue
+			}
+		}
+		kept = append(kept, record)
+	}
+	return kept
+}
+
+// SeverityCounts counts records by severity
+type SeverityCounts map[models.Severity]int
+
+// CountSeverities counts the records of each severity, leaving out records without a level
+func CountSeverities(records []*models.Record) SeverityCounts {
+	counts := SeverityCounts{}
+	for _, record := range records {
+		if severity, ok := record.Severity(); ok {
+			counts[severity]++
+		}
+	}
+	return counts
+}
+
+// String lists the counts from the highest severity down, like "ERROR 3, WARN 10"
+func (c SeverityCounts) String() string {
+	severities := models.Severities()
+	parts := []string{}
+	for i := len(severities) - 1; i >= 0; i-- {
+		if count := c[severities[i]]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", severities[i], count))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,45 @@
+package formatter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// levelRecords creates a record per level value, with an empty value for an unmatched record
+func levelRecords(values ...string) []*models.Record {
+	records := make([]*models.Record, len(values))
+	for i, value := range values {
+		records[i] = models.NewRecord(i+1, value)
+		if value != "" {
+			records[i].Set(&models.Field{Name: "lvl", Type: models.Level}, value)
+			records[i].Matched = true
+		}
+	}
+	return records
+}
+
+func TestFilterSeverity(t *testing.T) {
+	// Unrecognized levels like "bogus" are left out
+	records := levelRecords("debug", "W", "", "50", "info", "3", "bogus")
+
+	kept := FilterSeverity(records, models.SeverityWarn)
+	lines := []int{}
+	for _, record := range kept {
+		lines = append(lines, record.Line)
+	}
+	if want := []int{2, 3, 4, 6}; !slices.Equal(lines, want) {
+		t.Errorf("FilterSeverity() kept lines %v, want %v", lines, want)
+	}
+}
+
+func TestCountSeverities(t *testing.T) {
+	counts := CountSeverities(levelRecords("debug", "W", "", "50", "err", "info", "warning"))
+	if got, want := counts.String(), "ERROR 2, WARN 2, INFO 1, DEBUG 1"; got != want {
+		t.Errorf("CountSeverities() = %q, want %q", got, want)
+	}
+	if got := CountSeverities(levelRecords("")).String(); got != "" {
+		t.Errorf("CountSeverities() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,81 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// MismatchPolicy decides what happens to records that don't match the template
+type MismatchPolicy struct {
+	Action   string // One of the models.Mismatch* actions, empty passes records through
+	Marker   string // Prefix of marked records, defaults to models.DefaultMarker
+	Fallback string // Target template of marked records, using the @raw@ and @line@ fields
+
+	// Continue adds an appended line to a field of the previous record, like a
+	// stack trace, false if the line is appended to its output instead
+	Continue func(record *models.Record, line string) bool
+}
+
+// NewMismatchPolicy creates the policy configured by template literals
+func NewMismatchPolicy(literals models.TemplateLiterals) MismatchPolicy {
+	return MismatchPolicy{
+		Action:   literals.OnMismatch,
+		Marker:   literals.Marker,
+		Fallback: literals.Fallback,
+	}
+}
+
+// Apply applies the policy to the unmatched records of a list, returning the
+// records to output. Rejected records are passed to reject in order.
+func (p MismatchPolicy) Apply(records []*models.Record, reject func(record *models.Record) error) ([]*models.Record, error) {
+	if p.Action == "" || p.Action == models.MismatchPassthrough {
+		return records, nil
+	}
+
+	kept := make([]*models.Record, 0, len(records))
+	for _, record := range records {
+		if record.Matched {
+			kept = append(kept, record)
+			continue
+		}
+
+		switch p.Action {
+		case models.MismatchDrop:
+		case models.MismatchReject:
+			if err := reject(record); err != nil {
+				return nil, err
+			}
+		case models.MismatchAppend:
+			// Lines before the first record are passed through
+			if len(kept) == 0 {
+				kept = append(kept, record)
+				continue
+			}
+			previous := kept[len(kept)-1]
+			previous.Raw += "\n" + record.Raw
+			if p.Continue == nil || !p.Continue(previous, record.Raw) {
+				previous.Output += "\n" + record.Raw
+			}
+		case models.MismatchMark:
+			record.Output = p.mark(record)
+			kept = append(kept, record)
+		default:
+			kept = append(kept, record)
+		}
+	}
+	return kept, nil
+}
+
+// mark renders an unmatched record through the fallback template, or prefixes
+// it with the marker
+func (p MismatchPolicy) mark(record *models.Record) string {
+	if p.Fallback != "" {
+		return strings.NewReplacer("@raw@", record.Raw, "@line@", strconv.Itoa(record.Line)).Replace(p.Fallback)
+	}
+	if p.Marker != "" {
+		return p.Marker + record.Raw
+	}
+	return models.DefaultMarker + record.Raw
+}
@@ -0,0 +1,78 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// newRecords creates records from lines, lines starting with "!" don't match
+func newRecords(lines ...string) []*models.Record {
+	records := make([]*models.Record, len(lines))
+	for i, line := range lines {
+		records[i] = models.NewRecord(i+1, line)
+		if line[0] != '!' {
+			records[i].Output = "<" + line + ">"
+			records[i].Matched = true
+		}
+	}
+	return records
+}
+
+func TestMismatchPolicy_Apply(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       MismatchPolicy
+		want         []string
+		wantRejected []int
+	}{
+		{name: "Default", policy: MismatchPolicy{}, want: []string{"! head", "<a>", "! x", "! y", "<b>"}},
+		{name: "Passthrough", policy: MismatchPolicy{Action: models.MismatchPassthrough}, want: []string{"! head", "<a>", "! x", "! y", "<b>"}},
+		{name: "Drop", policy: MismatchPolicy{Action: models.MismatchDrop}, want: []string{"<a>", "<b>"}},
+		{name: "Append", policy: MismatchPolicy{Action: models.MismatchAppend}, want: []string{"! head", "<a>\n! x\n! y", "<b>"}},
+		{
+			name: "Append continuing a field",
+			policy: MismatchPolicy{Action: models.MismatchAppend, Continue: func(record *models.Record, line string) bool {
+				if line != "! x" {
+					return false
+				}
+				record.Output += " + " + line
+				return true
+			}},
+			want: []string{"! head", "<a> + ! x\n! y", "<b>"},
+		},
+		{name: "Mark", policy: MismatchPolicy{Action: models.MismatchMark}, want: []string{"[unmatched] ! head", "<a>", "[unmatched] ! x", "[unmatched] ! y", "<b>"}},
+		{name: "Custom marker", policy: MismatchPolicy{Action: models.MismatchMark, Marker: "? "}, want: []string{"? ! head", "<a>", "? ! x", "? ! y", "<b>"}},
+		{
+			name:   "Fallback template",
+			policy: MismatchPolicy{Action: models.MismatchMark, Fallback: "line @line@: @raw@"},
+			want:   []string{"line 1: ! head", "<a>", "line 3: ! x", "line 4: ! y", "<b>"},
+		},
+		{name: "Reject", policy: MismatchPolicy{Action: models.MismatchReject}, want: []string{"<a>", "<b>"}, wantRejected: []int{1, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejected := []int{}
+			records, err := tt.policy.Apply(newRecords("! head", "a", "! x", "! y", "b"), func(record *models.Record) error {
+				rejected = append(rejected, record.Line)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			got := []string{}
+			for _, record := range records {
+				got = append(got, record.Output)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+			if len(tt.wantRejected) > 0 && !reflect.DeepEqual(rejected, tt.wantRejected) {
+				t.Errorf("Apply() rejected = %v, want %v", rejected, tt.wantRejected)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package formatter
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/pkg/fileutil"
+)
+
+// chunksPerWorker is the number of chunks of input per worker, so that workers
+// finishing early pick up more work instead of waiting for slower ones
+const chunksPerWorker = 4
+
+// FormatRecordsParallel formats log lines like FormatRecords, parsing chunks of
+// lines on a number of workers. Records are returned in the order of the lines.
+// Parsers whose entries may span multiple lines are run on a single worker.
+func (f *TemplateFormatter) FormatRecordsParallel(logs []string, workers int) ([]*models.Record, error) {
+	recordParser, ok := f.concurrentParser(workers)
+	if !ok {
+		return f.FormatRecords(logs)
+	}
+
+	count := min(workers*chunksPerWorker, len(logs))
+	chunks, err := parallel(count, workers, func(i int) ([]*models.Record, error) {
+		lines := logs[i*len(logs)/count : (i+1)*len(logs)/count]
+		records := make([]*models.Record, len(lines))
+		for j, line := range lines {
+			records[j] = recordParser.ParseRecord(line)
+		}
+		return records, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return number(chunks), nil
+}
+
+// FormatFile reads and formats the lines of a file, returning the records and
+// the number of lines read. With more than one worker the file is split into
+// byte ranges aligned on line breaks, each read and parsed on its own, and
+// records are returned in the order of the file.
+func (f *TemplateFormatter) FormatFile(path string, workers int) ([]*models.Record, int, error) {
+	fileUtil := &fileutil.FileUtil{Options: f.readOptions}
+
+	recordParser, ok := f.concurrentParser(workers)
+	if !ok {
+		lines, err := fileUtil.ReadLines(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		records, err := f.FormatRecords(lines)
+		return records, len(lines), err
+	}
+
+	ranges, err := fileUtil.SplitChunks(path, workers*chunksPerWorker)
+	if err != nil {
+		return nil, 0, err
+	}
+	chunks, err := parallel(len(ranges), workers, func(i int) ([]*models.Record, error) {
+		records := []*models.Record{}
+		err := fileUtil.EachLineInChunk(path, ranges[i], func(line string) error {
+			records = append(records, recordParser.ParseRecord(line))
+			return nil
+		})
+		return records, err
+	})
+	if err != nil {
+		// Lines of a chunk are numbered from its start, count the lines of the chunks before
+		var failed *taskError
+		var lineErr *fileutil.LineError
+		if errors.As(err, &failed) && errors.As(err, &lineErr) {
+			for _, chunk := range chunks[:failed.index] {
+				lineErr.Line += len(chunk)
+			}
+		}
+		return nil, 0, err
+	}
+
+	records := number(chunks)
+	return records, len(records), nil
+}
+
+// concurrentParser returns the parser if lines can be parsed concurrently with
+// a number of workers
+func (f *TemplateFormatter) concurrentParser(workers int) (parser.RecordParser, bool) {
+	recordParser, ok := f.parser.(parser.RecordParser)
+	if !ok || workers <= 1 || !recordParser.SingleLine() {
+		return nil, false
+	}
+	return recordParser, true
+}
+
+// taskError is the error of a task run in parallel
+type taskError struct {
+	index int // Index of the task
+	err   error
+}
+
+func (e *taskError) Error() string {
+	return e.err.Error()
+}
+
+func (e *taskError) Unwrap() error {
+	return e.err
+}
+
+// parallel runs count tasks on a number of workers and returns their results
+// in the order of the tasks. If any task fails, the error of the first one is
+// returned as a taskError along with the results of all tasks.
+func parallel(count, workers int, task func(i int) ([]*models.Record, error)) ([][]*models.Record, error) {
+	results := make([][]*models.Record, count)
+	errs := make([]error, count)
+
+	tasks := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, count) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range tasks {
+				results[i], errs[i] = task(i)
+			}
+		}()
+	}
+	for i := range count {
+		tasks <- i
+	}
+	close(tasks)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return results, &taskError{index: i, err: err}
+		}
+	}
+	return results, nil
+}
+
+// number joins chunks of records and numbers them by the line they were read from
+func number(chunks [][]*models.Record) []*models.Record {
+	records := []*models.Record{}
+	for _, chunk := range chunks {
+		for _, record := range chunk {
+			record.Line = len(records) + 1
+			records = append(records, record)
+		}
+	}
+	return records
+}
@@ -0,0 +1,140 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/parser"
+	"github.com/gitKashish/golog/pkg/fileutil"
+)
+
+// sampleLogs returns lines matching the sample template, with every seventh one unmatched
+func sampleLogs(count int) []string {
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("2024-01-02 INFO request %d served", i)
+		if i%7 == 0 {
+			lines[i] = fmt.Sprintf("unmatched %d", i)
+		}
+	}
+	return lines
+}
+
+func newSampleFormatter(t *testing.T) *TemplateFormatter {
+	t.Helper()
+	p := parser.NewTemplateParser()
+	if err := p.SetTemplate("@date-string@ @level-string@ @message-string@", "[@level@] @message@"); err != nil {
+		t.Fatalf("SetTemplate() error = %v", err)
+	}
+	return NewTemplateFormatter(p)
+}
+
+// sameRecords fails the test unless records are equal in line, output and match
+func sameRecords(t *testing.T, got, want []*models.Record) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Line != want[i].Line || got[i].Output != want[i].Output || got[i].Matched != want[i].Matched {
+			t.Fatalf("record %d = line %d %q, want line %d %q", i, got[i].Line, got[i].Output, want[i].Line, want[i].Output)
+		}
+	}
+}
+
+func TestTemplateFormatter_FormatRecordsParallel(t *testing.T) {
+	formatter := newSampleFormatter(t)
+
+	tests := []struct {
+		name    string
+		lines   int
+		workers int
+	}{
+		{"no lines", 0, 4},
+		{"fewer lines than chunks", 3, 16},
+		{"many lines", 1000, 8},
+		{"single worker", 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := sampleLogs(tt.lines)
+			want, err := formatter.FormatRecords(logs)
+			if err != nil {
+				t.Fatalf("FormatRecords() error = %v", err)
+			}
+			got, err := formatter.FormatRecordsParallel(logs, tt.workers)
+			if err != nil {
+				t.Fatalf("FormatRecordsParallel() error = %v", err)
+			}
+			sameRecords(t, got, want)
+		})
+	}
+}
+
+func TestTemplateFormatter_FormatFile(t *testing.T) {
+	formatter := newSampleFormatter(t)
+	logs := sampleLogs(1000)
+
+	tests := []struct {
+		name    string
+		content string
+		workers int
+	}{
+		{"trailing line break", strings.Join(logs, "\n") + "\n", 8},
+		{"no trailing line break", strings.Join(logs, "\n"), 8},
+		{"crlf line breaks", strings.Join(logs, "\r\n"), 3},
+		{"single worker", strings.Join(logs, "\n"), 1},
+		{"empty file", "", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "input.log")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			want := []*models.Record{}
+			if tt.content != "" {
+				var err error
+				want, err = formatter.FormatRecords(logs)
+				if err != nil {
+					t.Fatalf("FormatRecords() error = %v", err)
+				}
+			}
+
+			got, lines, err := formatter.FormatFile(path, tt.workers)
+			if err != nil {
+				t.Fatalf("FormatFile() error = %v", err)
+			}
+			if lines != len(want) {
+				t.Errorf("FormatFile() read %d lines, want %d", lines, len(want))
+			}
+			sameRecords(t, got, want)
+		})
+	}
+}
+
+func TestTemplateFormatter_FormatFile_LineError(t *testing.T) {
+	logs := sampleLogs(1000)
+	logs[899] = strings.Repeat("x", 200)
+	path := filepath.Join(t.TempDir(), "input.log")
+	if err := os.WriteFile(path, []byte(strings.Join(logs, "\n")), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// The line is numbered from the start of the file, not of its chunk
+	for _, workers := range []int{1, 8} {
+		formatter := newSampleFormatter(t)
+		formatter.SetReadOptions(fileutil.ReadOptions{MaxLineSize: 100})
+		_, _, err := formatter.FormatFile(path, workers)
+		var lineErr *fileutil.LineError
+		if !errors.As(err, &lineErr) || lineErr.Line != 900 {
+			t.Errorf("FormatFile() with %d workers error = %v, want error at line 900", workers, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package formatter
+
+import (
+	"cmp"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// conditionRegex matches conditions like status>=500 or client.ip=10.0.0.0/8
+var conditionRegex = regexp.MustCompile(`^\s*([\w.]+)\s*(!=|>=|<=|=|>|<)\s*(.*?)\s*$`)
+
+// Condition compares the value of a field of records with a value
+type Condition struct {
+	Field    string
+	Operator string // One of =, !=, >, >=, < or <=
+	Value    string
+}
+
+// ParseCondition parses a condition like status>=500, duration>1s or
+// client=10.0.0.0/8
+func ParseCondition(expression string) (*Condition, error) {
+	match := conditionRegex.FindStringSubmatch(expression)
+	if match == nil {
+		return nil, fmt.Errorf("invalid condition `%s`, expected a field, an operator (=, !=, >, >=, <, <=) and a value", expression)
+	}
+	return &Condition{Field: match[1], Operator: match[2], Value: match[3]}, nil
+}
+
+// Match reports whether a record satisfies the condition. Values are compared
+// according to the type of the field: numbers, durations, sizes, levels and
+// timestamps by their magnitude, and IP addresses by address or, with = and !=,
+// by whether a CIDR prefix like 10.0.0.0/8 contains them. Other values, and
+// values their type rejects, are compared as text. Records without the field
+// don't match.
+func (c *Condition) Match(record *models.Record) bool {
+	value, ok := record.Value(c.Field)
+	if !ok {
+		return false
+	}
+	field, _ := record.Field(c.Field)
+
+	if field.Type == models.IP && (c.Operator == "=" || c.Operator == "!=") {
+		if prefix, err := netip.ParsePrefix(c.Value); err == nil {
+			addr, err := netip.ParseAddr(strings.TrimSpace(value))
+			return err == nil && prefix.Contains(addr.Unmap()) == (c.Operator == "=")
+		}
+	}
+
+	order, ok := compareValues(field.Type, value, c.Value)
+	if !ok {
+		order = strings.Compare(value, c.Value)
+	}
+	switch c.Operator {
+	case "=":
+		return order == 0
+	case "!=":
+		return order != 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	case "<":
+		return order < 0
+	}
+	return order <= 0
+}
+
+// compareValues compares two values of a field type, false if the type has no
+// order of its own or either value is invalid for it
+func compareValues(fieldType models.FieldType, a, b string) (int, bool) {
+	switch fieldType {
+	case models.Number:
+		return compareParsed(a, b, func(value string) (float64, error) {
+			return strconv.ParseFloat(strings.TrimSpace(value), 64)
+		})
+	case models.Duration:
+		return compareParsed(a, b, models.ParseDuration)
+	case models.Bytes:
+		return compareParsed(a, b, models.ParseBytes)
+	case models.Level:
+		return compareParsed(a, b, func(value string) (models.Severity, error) {
+			severity, ok := models.ParseSeverity(value)
+			if !ok {
+				return severity, fmt.Errorf("invalid level `%s`", value)
+			}
+			return severity, nil
+		})
+	case models.Timestamp:
+		first, errA := models.ParseTimestamp(a)
+		second, errB := models.ParseTimestamp(b)
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		return first.Compare(second), true
+	case models.IP:
+		first, errA := netip.ParseAddr(strings.TrimSpace(a))
+		second, errB := netip.ParseAddr(strings.TrimSpace(b))
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		return first.Unmap().Compare(second.Unmap()), true
+	case models.Bool:
+		return compareParsed(a, b, func(value string) (int, error) {
+			parsed, err := models.ParseBool(value)
+			if parsed {
+				return 1, err
+			}
+			return 0, err
+		})
+	}
+	return 0, false
+}
+
+// compareParsed compares two values once parsed
+func compareParsed[T cmp.Ordered](a, b string, parse func(string) (T, error)) (int, bool) {
+	first, errA := parse(a)
+	second, errB := parse(b)
+	if errA != nil || errB != nil {
+		return 0, false
+	}
+	return cmp.Compare(first, second), true
+}
+
+// FilterWhere keeps the records satisfying all conditions
+func FilterWhere(records []*models.Record, conditions []*Condition) []*models.Record {
+	kept := make([]*models.Record, 0, len(records))
+	for _, record := range records {
+		matches := true
+		for _, condition := range conditions {
+			if !condition.Match(record) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			kept = append(kept, record)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       Condition
+		wantErr    bool
+	}{
+		{expression: "status>=500", want: Condition{Field: "status", Operator: ">=", Value: "500"}},
+		{expression: "client = 10.0.0.0/8", want: Condition{Field: "client", Operator: "=", Value: "10.0.0.0/8"}},
+		{expression: "url.path!=/health", want: Condition{Field: "url.path", Operator: "!=", Value: "/health"}},
+		{expression: "message=a=b", want: Condition{Field: "message", Operator: "=", Value: "a=b"}},
+		{expression: "took<", want: Condition{Field: "took", Operator: "<", Value: ""}},
+		{expression: "status", wantErr: true},
+		{expression: ">500", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := ParseCondition(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCondition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("ParseCondition() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondition_Match(t *testing.T) {
+	record := models.NewRecord(1, "")
+	record.Set(&models.Field{Name: "client", Type: models.IP}, "10.1.2.3")
+	record.Set(&models.Field{Name: "took", Type: models.Duration}, "1500ms")
+	record.Set(&models.Field{Name: "size", Type: models.Bytes}, "2048")
+	record.Set(&models.Field{Name: "status", Type: models.Number}, "503")
+	record.Set(&models.Field{Name: "level", Type: models.Level}, "W")
+	record.Set(&models.Field{Name: "cached", Type: models.Bool}, "yes")
+	record.Set(&models.Field{Name: "path", Type: models.String}, "/users")
+	record.Set(&models.Field{Name: "rejected", Type: models.Duration}, "soon")
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: "client=10.0.0.0/8", want: true},
+		{expression: "client!=10.0.0.0/8", want: false},
+		{expression: "client=192.168.0.0/16", want: false},
+		{expression: "client=10.1.2.3", want: true},
+		{expression: "client>10.1.2.2", want: true},
+		{expression: "took>1s", want: true},
+		{expression: "took<=1500ms", want: true},
+		{expression: "took>2s", want: false},
+		{expression: "size>=2KiB", want: true},
+		{expression: "size<1KB", want: false},
+		{expression: "status>=500", want: true},
+		{expression: "status<500", want: false},
+		{expression: "level>=warn", want: true},
+		{expression: "level>warning", want: false},
+		{expression: "cached=true", want: true},
+		{expression: "path=/users", want: true},
+		{expression: "path!=/users", want: false},
+		{expression: "rejected=soon", want: true},
+		{expression: "missing!=anything", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			condition, err := ParseCondition(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseCondition() error = %v", err)
+			}
+			if got := condition.Match(record); got != tt.want {
+				t.Errorf("Condition.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterWhere(t *testing.T) {
+	records := levelRecords("debug", "W", "", "error")
+	conditions := []*Condition{
+		{Field: "lvl", Operator: ">=", Value: "warn"},
+		{Field: "lvl", Operator: "!=", Value: "fatal"},
+	}
+
+	lines := []int{}
+	for _, record := range FilterWhere(records, conditions) {
+		lines = append(lines, record.Line)
+	}
+	if want := []int{2, 4}; !slices.Equal(lines, want) {
+		t.Errorf("FilterWhere() kept lines %v, want %v", lines, want)
+	}
+}
@@ -0,0 +1,288 @@
+package grouping
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/patterns"
+)
+
+// DefaultFrames is the number of stack frames making up a fingerprint
+const DefaultFrames = 5
+
+var (
+	// at com.example.Service.call(Service.java:42) or at handler (/app/index.js:10:5)
+	atFrameRegex = regexp.MustCompile(`^\s*at\s+(?:async\s+|new\s+)?(\S+)`)
+	// File "/app/main.py", line 10, in handler
+	pythonFrameRegex = regexp.MustCompile(`^\s*File "([^"]+)", line \d+, in (\S+)`)
+	// main.(*Server).handle(0xc000010000, 0x1)
+	goFrameRegex = regexp.MustCompile(`^([\w./*()-]+\.[\w*()-]+)\(.*\)$`)
+	// Line numbers, column numbers and memory offsets vary between builds
+	locationRegex = regexp.MustCompile(`(:\d+)+$|\+0x[0-9a-f]+$`)
+)
+
+// Options configures a Grouper
+type Options struct {
+	By            []string  // Fields making up the fingerprint, stack frames or the message pattern if empty
+	LevelField    string    // Field holding the severity, detected if empty
+	MessageField  string    // Field holding the message, used for fingerprints without frames
+	AffectedField string    // Field whose distinct values are counted per group
+	TimeField     string    // Field holding the time, the first timestamp field if empty
+	Since         time.Time // Records before this time are ignored, zero for all
+	Frames        int       // Stack frames making up a fingerprint, DefaultFrames if 0
+
+	// Continue adds a continuation line to a field of the record it continues,
+	// like a stack trace, so that its sub-fields like trace.top can be grouped by
+	Continue func(record *models.Record, line string) bool
+}
+
+// Group is a set of error records sharing a fingerprint
+type Group struct {
+	Fingerprint string
+	Count       int
+	First       time.Time      // Earliest time of the records, zero if unknown
+	Last        time.Time      // Latest time of the records, zero if unknown
+	Affected    map[string]int // Number of records by value of the affected field
+	Example     *models.Record // First record of the group
+	Stack       []string       // Continuation lines of the first record
+}
+
+// ValueCount is a value of the affected field and the number of records holding it
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// TopAffected returns the n most common values of the affected field, all if n is 0
+func (g *Group) TopAffected(n int) []ValueCount {
+	values := make([]ValueCount, 0, len(g.Affected))
+	for value, count := range g.Affected {
+		values = append(values, ValueCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if n > 0 && len(values) > n {
+		values = values[:n]
+	}
+	return values
+}
+
+// Grouper groups error records by fingerprint. Records are added in order, and
+// unmatched lines following a record are taken as its continuation, like the
+// lines of a stack trace.
+type Grouper struct {
+	options Options
+	groups  map[string]*Group
+	order   []*Group // Groups in order of first appearance
+
+	pending *models.Record // Last record, waiting for continuation lines
+	stack   []string       // Continuation lines of the pending record
+	untimed int            // Error records left out by Since for lack of a time
+}
+
+// NewGrouper creates a new Grouper
+func NewGrouper(options Options) *Grouper {
+	if options.Frames <= 0 {
+		options.Frames = DefaultFrames
+	}
+	return &Grouper{
+		options: options,
+		groups:  make(map[string]*Group),
+	}
+}
+
+// Add adds the next record. Unmatched records continue the previous record and
+// are ignored if there is none.
+func (g *Grouper) Add(record *models.Record) {
+	if !record.Matched {
+		if g.pending != nil {
+			g.stack = append(g.stack, record.Raw)
+			if g.options.Continue != nil {
+				g.options.Continue(g.pending, record.Raw)
+			}
+		}
+		return
+	}
+
+	g.Flush()
+	g.pending = record
+}
+
+// Flush groups the pending record, it must be called after the last record is added
+func (g *Grouper) Flush() {
+	if g.pending == nil {
+		return
+	}
+	record, stack := g.pending, g.stack
+	g.pending, g.stack = nil, nil
+
+	frames := Frames(stack)
+	if !g.isError(record, frames) {
+		return
+	}
+
+	timestamp, hasTime := record.Time(g.options.TimeField)
+	if !g.options.Since.IsZero() {
+		if !hasTime {
+			g.untimed++
+			return
+		}
+		if timestamp.Before(g.options.Since) {
+			return
+		}
+	}
+
+	fingerprint := g.fingerprint(record, frames)
+	group, exists := g.groups[fingerprint]
+	if !exists {
+		group = &Group{
+			Fingerprint: fingerprint,
+			Affected:    make(map[string]int),
+			Example:     record,
+			Stack:       stack,
+		}
+		g.groups[fingerprint] = group
+		g.order = append(g.order, group)
+	}
+
+	group.Count++
+	if hasTime {
+		if group.First.IsZero() || timestamp.Before(group.First) {
+			group.First = timestamp
+		}
+		if timestamp.After(group.Last) {
+			group.Last = timestamp
+		}
+	}
+	if value, ok := record.Value(g.options.AffectedField); ok && value != "" {
+		group.Affected[value]++
+	}
+}
+
+// Groups returns all groups sorted by decreasing count
+func (g *Grouper) Groups() []*Group {
+	groups := make([]*Group, len(g.order))
+	copy(groups, g.order)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
+
+// Untimed returns the number of error records left out because Since is set
+// and their time couldn't be found
+func (g *Grouper) Untimed() int {
+	return g.untimed
+}
+
+// isError checks whether a record is an error: it has a stack trace or an error
+// severity. With fingerprint fields it must hold all of them, and records
+// without a known severity are errors too.
+func (g *Grouper) isError(record *models.Record, frames []string) bool {
+	severity, known := g.severity(record)
+	if len(g.options.By) > 0 {
+		for _, name := range g.options.By {
+			if value, ok := record.Value(name); !ok || value == "" {
+				return false
+			}
+		}
+		return len(frames) > 0 || !known || severity >= models.SeverityError
+	}
+	return len(frames) > 0 || severity >= models.SeverityError
+}
+
+// severity returns the severity of a record from the level field, detected if
+// not set
+func (g *Grouper) severity(record *models.Record) (models.Severity, bool) {
+	if g.options.LevelField == "" {
+		return record.Severity()
+	}
+	if value, ok := record.Value(g.options.LevelField); ok {
+		return models.ParseSeverity(value)
+	}
+	return models.SeverityUnknown, false
+}
+
+// fingerprint identifies the error of a record by the fingerprint fields, its
+// top stack frames or the pattern of its message, in that order of preference
+func (g *Grouper) fingerprint(record *models.Record, frames []string) string {
+	if len(g.options.By) > 0 {
+		parts := make([]string, len(g.options.By))
+		for i, name := range g.options.By {
+			parts[i], _ = record.Value(name)
+		}
+		return strings.Join(parts, " | ")
+	}
+
+	if len(frames) > 0 {
+		if len(frames) > g.options.Frames {
+			frames = frames[:g.options.Frames]
+		}
+		return strings.Join(frames, " < ")
+	}
+
+	message, ok := record.Value(g.options.MessageField)
+	if !ok {
+		message = record.Raw
+	}
+	return strings.Join(patterns.Tokenize(message), " ")
+}
+
+// Frames returns the normalized frames of stack trace lines, innermost first.
+// Java, JavaScript, Python and Go traces are recognized, and line numbers and
+// offsets are removed so the same trace from another build matches.
+func Frames(lines []string) []string {
+	frames := []string{}
+	for _, line := range lines {
+		var frame string
+		if match := atFrameRegex.FindStringSubmatch(line); match != nil {
+			// Keep the function, or the location of anonymous functions
+			frame = match[1]
+			if i := strings.Index(frame, "("); i > 0 {
+				frame = frame[:i]
+			}
+			frame = strings.Trim(frame, "()")
+		} else if match := pythonFrameRegex.FindStringSubmatch(line); match != nil {
+			frame = match[1] + ":" + match[2]
+		} else if match := goFrameRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			frame = match[1]
+		} else {
+			continue
+		}
+		frames = append(frames, locationRegex.ReplaceAllString(frame, ""))
+	}
+
+	// Python prints the innermost frame last
+	if len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "Traceback") {
+		for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+			frames[i], frames[j] = frames[j], frames[i]
+		}
+	}
+	return frames
+}
+
+// ParseSince parses the start of a time range, either a duration before now
+// like "90m", "24h" or "7d", or a timestamp
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	if t, err := models.ParseTimestamp(value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time `%s`, expected a duration like 24h or a timestamp", value)
+}
@@ -0,0 +1,246 @@
+package grouping
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// newRecord creates a matched record with string fields from "name=value" pairs
+// and a timestamp field "ts"
+func newRecord(ts string, pairs ...string) *models.Record {
+	record := models.NewRecord(0, ts+" "+strings.Join(pairs, " "))
+	record.Set(&models.Field{Name: "ts", Type: models.Timestamp}, ts)
+	for _, pair := range pairs {
+		name, value, _ := strings.Cut(pair, "=")
+		record.Set(&models.Field{Name: name, Type: models.String}, value)
+	}
+	record.Matched = true
+	return record
+}
+
+// continuation creates an unmatched record for a continuation line
+func continuation(line string) *models.Record {
+	return models.NewRecord(0, line)
+}
+
+func TestGrouper_Groups(t *testing.T) {
+	records := []*models.Record{
+		newRecord("2024-01-02T10:00:00Z", "level=INFO", "api=users", "message=started"),
+		newRecord("2024-01-02T10:00:01Z", "level=ERROR", "api=users", "message=request failed"),
+		continuation("java.lang.NullPointerException: boom"),
+		continuation("\tat com.example.Users.load(Users.java:42)"),
+		continuation("\tat com.example.Api.handle(Api.java:10)"),
+		newRecord("2024-01-02T10:00:02Z", "level=error", "api=orders", "message=timeout after 300ms"),
+		newRecord("2024-01-02T10:00:03Z", "level=WARN", "api=orders", "message=request failed"),
+		// Same trace from another build
+		continuation("java.lang.NullPointerException: boom"),
+		continuation("\tat com.example.Users.load(Users.java:44)"),
+		continuation("\tat com.example.Api.handle(Api.java:12)"),
+		newRecord("2024-01-02T10:00:04Z", "level=ERROR", "api=users", "message=timeout after 20ms"),
+		newRecord("2024-01-02T10:00:05Z", "level=ERROR", "api=orders", "message=timeout after 1ms"),
+	}
+
+	grouper := NewGrouper(Options{MessageField: "message", AffectedField: "api"})
+	for _, record := range records {
+		grouper.Add(record)
+	}
+	grouper.Flush()
+
+	groups := grouper.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("Grouper.Groups() returned %d groups, want 2", len(groups))
+	}
+
+	timeouts, traces := groups[0], groups[1]
+	if timeouts.Fingerprint != "timeout after <NUM>" || timeouts.Count != 3 {
+		t.Errorf("Grouper.Groups()[0] = %q x%d, want timeouts x3", timeouts.Fingerprint, timeouts.Count)
+	}
+	wantAffected := []ValueCount{{Value: "orders", Count: 2}, {Value: "users", Count: 1}}
+	if got := timeouts.TopAffected(0); !reflect.DeepEqual(got, wantAffected) {
+		t.Errorf("Group.TopAffected() = %v, want %v", got, wantAffected)
+	}
+
+	if traces.Fingerprint != "com.example.Users.load < com.example.Api.handle" || traces.Count != 2 {
+		t.Errorf("Grouper.Groups()[1] = %q x%d, want stack trace x2", traces.Fingerprint, traces.Count)
+	}
+	if !traces.First.Equal(time.Date(2024, 1, 2, 10, 0, 1, 0, time.UTC)) || !traces.Last.Equal(time.Date(2024, 1, 2, 10, 0, 3, 0, time.UTC)) {
+		t.Errorf("Grouper.Groups()[1] seen %v to %v, want 10:00:01 to 10:00:03", traces.First, traces.Last)
+	}
+	if traces.Example != records[1] || len(traces.Stack) != 3 {
+		t.Errorf("Grouper.Groups()[1] example = %q with %d stack lines, want first record with 3", traces.Example.Raw, len(traces.Stack))
+	}
+}
+
+func TestGrouper_By(t *testing.T) {
+	grouper := NewGrouper(Options{
+		By:    []string{"code", "api"},
+		Since: time.Date(2024, 1, 2, 10, 0, 1, 0, time.UTC),
+	})
+	grouper.Add(newRecord("2024-01-02T10:00:00Z", "code=ETIMEDOUT", "api=users"))
+	grouper.Add(newRecord("2024-01-02T10:00:01Z", "code=ETIMEDOUT", "api=users"))
+	grouper.Add(newRecord("2024-01-02T10:00:02Z", "code=ETIMEDOUT", "api=orders"))
+	grouper.Add(newRecord("2024-01-02T10:00:03Z", "api=users"))
+	grouper.Add(newRecord("2024-01-02T10:00:04Z", "code=ECONNRESET", "api=users"))
+	grouper.Add(newRecord("2024-01-02T10:00:05Z", "code=ETIMEDOUT", "api=users"))
+	grouper.Flush()
+
+	got := map[string]int{}
+	for _, group := range grouper.Groups() {
+		got[group.Fingerprint] = group.Count
+	}
+	want := map[string]int{"ETIMEDOUT | users": 2, "ETIMEDOUT | orders": 1, "ECONNRESET | users": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Grouper.Groups() = %v, want %v", got, want)
+	}
+}
+
+func TestGrouper_BySeverity(t *testing.T) {
+	// Records below error are left out, records without a level are kept
+	grouper := NewGrouper(Options{By: []string{"code"}})
+	grouper.Add(newRecord("2024-01-02T10:00:00Z", "level=INFO", "code=ETIMEDOUT"))
+	grouper.Add(newRecord("2024-01-02T10:00:01Z", "level=ERROR", "code=ETIMEDOUT"))
+	grouper.Add(newRecord("2024-01-02T10:00:02Z", "code=ETIMEDOUT"))
+	grouper.Add(newRecord("2024-01-02T10:00:03Z", "level=WARN", "code=ECONNRESET"))
+	grouper.Flush()
+
+	groups := grouper.Groups()
+	if len(groups) != 1 || groups[0].Fingerprint != "ETIMEDOUT" || groups[0].Count != 2 {
+		t.Errorf("Grouper.Groups() = %+v, want ETIMEDOUT x2", groups)
+	}
+}
+
+func TestGrouper_Untimed(t *testing.T) {
+	grouper := NewGrouper(Options{
+		MessageField: "message",
+		Since:        time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+	})
+	grouper.Add(newRecord("2024-01-02T10:00:01Z", "level=ERROR", "message=boom"))
+	grouper.Add(newRecord("yesterday", "level=ERROR", "message=boom"))
+	grouper.Add(newRecord("yesterday", "level=INFO", "message=started"))
+	grouper.Flush()
+
+	groups := grouper.Groups()
+	if len(groups) != 1 || groups[0].Count != 1 {
+		t.Errorf("Grouper.Groups() = %+v, want boom x1", groups)
+	}
+	// Only error records are counted
+	if got := grouper.Untimed(); got != 1 {
+		t.Errorf("Grouper.Untimed() = %d, want 1", got)
+	}
+}
+
+func TestGrouper_Continue(t *testing.T) {
+	// Continuation lines complete a field the records are grouped by
+	grouper := NewGrouper(Options{
+		By: []string{"top"},
+		Continue: func(record *models.Record, line string) bool {
+			record.Set(&models.Field{Name: "top", Type: models.String}, strings.TrimSpace(line))
+			return true
+		},
+	})
+	grouper.Add(newRecord("2024-01-02T10:00:00Z", "msg=boom"))
+	grouper.Add(continuation("  at handler (/app/index.js:10:5)"))
+	grouper.Add(newRecord("2024-01-02T10:00:01Z", "msg=done"))
+	grouper.Flush()
+
+	groups := grouper.Groups()
+	if len(groups) != 1 || groups[0].Fingerprint != "at handler (/app/index.js:10:5)" || len(groups[0].Stack) != 1 {
+		t.Errorf("Grouper.Groups() = %+v, want one group of the continued record", groups)
+	}
+}
+
+func TestFrames(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name: "Java",
+			lines: []string{
+				"java.lang.IllegalStateException: closed",
+				"\tat com.example.Pool.get(Pool.java:87)",
+				"\tat com.example.Service.run(Service.java:12)",
+				"Caused by: java.io.IOException",
+			},
+			want: []string{"com.example.Pool.get", "com.example.Service.run"},
+		},
+		{
+			name: "JavaScript",
+			lines: []string{
+				"TypeError: x is undefined",
+				"    at async handler (/app/index.js:10:5)",
+				"    at /app/server.js:44:12",
+			},
+			want: []string{"handler", "/app/server.js"},
+		},
+		{
+			name: "Python",
+			lines: []string{
+				"Traceback (most recent call last):",
+				`  File "/app/main.py", line 10, in main`,
+				"    run()",
+				`  File "/app/jobs.py", line 3, in run`,
+				"ZeroDivisionError: division by zero",
+			},
+			want: []string{"/app/jobs.py:run", "/app/main.py:main"},
+		},
+		{
+			name: "Go",
+			lines: []string{
+				"goroutine 1 [running]:",
+				"main.(*Server).handle(0xc000010000, 0x1)",
+				"\t/app/server.go:42 +0x1d",
+				"main.main()",
+				"\t/app/main.go:9 +0x25",
+			},
+			want: []string{"main.(*Server).handle", "main.main"},
+		},
+		{
+			name:  "No frames",
+			lines: []string{"just a wrapped message"},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Frames(tt.lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Frames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "24h", want: now.Add(-24 * time.Hour)},
+		{value: "90m", want: now.Add(-90 * time.Minute)},
+		{value: "7d", want: time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)},
+		{value: "2024-01-02T10:00:00Z", want: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{value: "yesterday", wantErr: true},
+		{value: "-5h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,427 @@
+package infer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/parser"
+)
+
+// minShare is the share of sample lines that must follow the inferred skeleton
+const minShare = 0.8
+
+// delimiters are the characters separating tokens besides whitespace
+const delimiters = `[]()<>|"',;`
+
+var (
+	dateRegex  = regexp.MustCompile(`^\d{4}[-/]\d{2}[-/]\d{2}$`)
+	clockRegex = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}([.,]\d+)?(Z|[+-]\d{2}:?\d{2})?$`)
+	spaceRegex = regexp.MustCompile(`\s+`)
+)
+
+// levels holds the severity names used to recognize level fields
+var levels = map[string]bool{
+	"trace": true, "debug": true, "info": true, "notice": true, "warn": true, "warning": true,
+	"error": true, "err": true, "fatal": true, "critical": true, "crit": true, "panic": true,
+}
+
+// Result is a template inferred from sample lines
+type Result struct {
+	Literals models.TemplateLiterals
+	Lines    int // Number of sample lines
+	Matched  int // Number of sample lines matching the inferred template
+}
+
+// MatchRate returns the share of sample lines matching the inferred template
+func (r Result) MatchRate() float64 {
+	if r.Lines == 0 {
+		return 0
+	}
+	return float64(r.Matched) / float64(r.Lines)
+}
+
+// tokens is a line split into words and the delimiters around them.
+// A line is made of delims[0] words[0] delims[1] words[1] ... delims[n].
+type tokens struct {
+	words  []string
+	delims []string
+}
+
+// column is a position of the skeleton shared by the sample lines
+type column struct {
+	delim  string   // Delimiter preceding the column
+	values []string // Value of the column in each line following the skeleton
+	width  int      // Fixed width of the values, 0 if they differ in length
+}
+
+// Infer infers a source template from sample lines. Lines are split into words
+// at whitespace and punctuation, positions holding the same word in every line
+// become literals and the others become fields. The words following the
+// positions shared by most lines are captured by a trailing message field.
+func Infer(lines []string) (Result, error) {
+	samples := []tokens{}
+	words := 0
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			sample := tokenize(line)
+			samples = append(samples, sample)
+			words += len(sample.words)
+		}
+	}
+	if len(samples) == 0 {
+		return Result{}, fmt.Errorf("no sample lines to infer a template from")
+	}
+	if words == 0 {
+		return Result{}, fmt.Errorf("no tokens to infer a template from, the sample lines only hold delimiters")
+	}
+
+	columns, trailing, tail := skeleton(samples)
+	source := buildSource(columns, trailing, tail)
+	target := buildTarget(source)
+	if target == "" {
+		return Result{}, fmt.Errorf("no fields to infer, the sample lines don't differ in any word")
+	}
+
+	result := Result{
+		Literals: models.TemplateLiterals{Source: source, Target: target},
+		Lines:    len(samples),
+	}
+
+	// Measure how well the template matches the sample
+	p := parser.NewTemplateParser()
+	if err := p.SetLiterals(result.Literals); err != nil {
+		return result, fmt.Errorf("inferred an invalid template: %w", err)
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" && p.ParseRecord(line).Matched {
+			result.Matched++
+		}
+	}
+	return result, nil
+}
+
+// tokenize splits a line into words and delimiters. Anything from an opening
+// brace at the start of a word is kept as a single word to keep JSON intact.
+func tokenize(line string) tokens {
+	t := tokens{}
+	delim := strings.Builder{}
+	i := 0
+	for i < len(line) {
+		if isDelimiter(line[i]) {
+			delim.WriteByte(line[i])
+			i++
+			continue
+		}
+
+		t.delims = append(t.delims, delim.String())
+		delim.Reset()
+
+		if line[i] == '{' {
+			t.words = append(t.words, strings.TrimRight(line[i:], " \t"))
+			i = len(strings.TrimRight(line, " \t"))
+			continue
+		}
+
+		start := i
+		for i < len(line) && !isDelimiter(line[i]) {
+			i++
+		}
+		t.words = append(t.words, line[start:i])
+	}
+	t.delims = append(t.delims, delim.String())
+	return t
+}
+
+// isDelimiter checks whether a character separates words
+func isDelimiter(c byte) bool {
+	return c == ' ' || c == '\t' || strings.IndexByte(delimiters, c) >= 0
+}
+
+// normalize collapses whitespace of a delimiter, as templates match any amount of it
+func normalize(delim string) string {
+	return spaceRegex.ReplaceAllString(delim, " ")
+}
+
+// skeleton finds the columns shared by most sample lines. It returns the columns,
+// the delimiter ending every line if there is no tail, and whether the last column
+// captures the rest of the line.
+func skeleton(samples []tokens) ([]*column, string, bool) {
+	// Find the most words whose preceding delimiters are shared by enough lines
+	count, mode := 1, ""
+	for k := 1; ; k++ {
+		signature, matching := modeSignature(samples, k)
+		if float64(matching) < minShare*float64(len(samples)) {
+			break
+		}
+		count, mode = k, signature
+	}
+	if mode == "" {
+		mode, _ = modeSignature(samples, count)
+	}
+
+	// Collect column values of the lines following the skeleton
+	var columns []*column
+	trailing, tail := "", false
+	for _, sample := range samples {
+		if signature(sample, count) != mode {
+			continue
+		}
+		if columns == nil {
+			columns = make([]*column, count)
+			for i := range columns {
+				columns[i] = &column{delim: sample.delims[i]}
+			}
+			trailing = sample.delims[count]
+		}
+
+		for i := 0; i < count; i++ {
+			columns[i].values = append(columns[i].values, sample.words[i])
+		}
+
+		// The last column holds the rest of lines that go on after it
+		if len(sample.words) > count || normalize(sample.delims[count]) != normalize(trailing) {
+			tail = true
+		}
+	}
+	if tail {
+		trailing = ""
+	}
+	return columns, trailing, tail
+}
+
+// modeSignature returns the most common signature of the first k words and the
+// number of samples sharing it
+func modeSignature(samples []tokens, k int) (string, int) {
+	counts := map[string]int{}
+	mode := ""
+	for _, sample := range samples {
+		if len(sample.words) < k {
+			continue
+		}
+		key := signature(sample, k)
+		counts[key]++
+		if counts[key] > counts[mode] {
+			mode = key
+		}
+	}
+	return mode, counts[mode]
+}
+
+// signature identifies the delimiters preceding the first k words of a sample
+func signature(sample tokens, k int) string {
+	if len(sample.words) < k {
+		return ""
+	}
+	delims := make([]string, k)
+	for i := range delims {
+		delims[i] = normalize(sample.delims[i])
+	}
+	return "\x00" + strings.Join(delims, "\x00")
+}
+
+// buildSource creates the source template from the skeleton
+func buildSource(columns []*column, trailing string, tail bool) string {
+	columns = mergeTimestamps(columns, tail)
+
+	source := strings.Builder{}
+	names := map[string]int{}
+	counter := 0
+	for i, c := range columns {
+		source.WriteString(c.delim)
+		isTail := tail && i == len(columns)-1
+
+		if constant(c.values) && !isTail && !strings.Contains(c.values[0], "@") {
+			source.WriteString(c.values[0])
+			continue
+		}
+
+		// Common text around untyped values up to punctuation belongs to the skeleton
+		prefix, suffix := "", ""
+		fieldType, name := guess(c.values)
+		if !isTail && fieldType == models.String && name == "" {
+			prefix, suffix = affixes(c.values)
+			fieldType, name = guess(c.values)
+		}
+		if isTail {
+			fieldType, name = models.String, "message"
+			if allValues(c.values, isJSON) {
+				fieldType, name = models.JSON, "data"
+			}
+		}
+		if name == "" {
+			counter++
+			name = fmt.Sprintf("field%d", counter)
+		}
+		names[name]++
+		if names[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, names[name])
+		}
+
+		source.WriteString(prefix)
+		if c.width > 0 {
+			source.WriteString(fmt.Sprintf("@%s-%s{%d}@", name, fieldType, c.width))
+		} else {
+			source.WriteString(fmt.Sprintf("@%s-%s@", name, fieldType))
+		}
+		source.WriteString(suffix)
+	}
+	source.WriteString(trailing)
+	return source.String()
+}
+
+// mergeTimestamps joins date and time columns separated by a space into a single
+// fixed-width timestamp column, as a regular field would stop at the space
+func mergeTimestamps(columns []*column, tail bool) []*column {
+	last := len(columns) - 1
+	if tail {
+		last-- // The tail column captures more than its first word
+	}
+	for i := 0; i+1 <= last; i++ {
+		date, clock := columns[i], columns[i+1]
+		if normalize(clock.delim) != " " || len(date.values) != len(clock.values) ||
+			!allValues(date.values, dateRegex.MatchString) || !allValues(clock.values, clockRegex.MatchString) {
+			continue
+		}
+
+		merged := make([]string, len(date.values))
+		for j := range merged {
+			merged[j] = date.values[j] + " " + clock.values[j]
+		}
+		if !sameLength(merged) {
+			continue
+		}
+
+		date.values = merged
+		date.width = len(merged[0])
+		clock.values = nil
+		clock.delim = ""
+	}
+
+	// Drop the columns merged into their predecessor
+	kept := []*column{}
+	for _, c := range columns {
+		if c.values != nil {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// buildTarget creates a target template listing every field of the source template
+func buildTarget(source string) string {
+	fieldRegex := regexp.MustCompile(`@(\w+)-\w+(?:\{\d+\})?@`)
+	placeholders := []string{}
+	for _, match := range fieldRegex.FindAllStringSubmatch(source, -1) {
+		placeholders = append(placeholders, "@"+match[1]+"@")
+	}
+	return strings.Join(placeholders, " | ")
+}
+
+// guess returns the field type and a descriptive name for column values
+func guess(values []string) (models.FieldType, string) {
+	switch {
+	case allValues(values, isNumber):
+		return models.Number, ""
+	case allValues(values, isTimestamp):
+		return models.Timestamp, "timestamp"
+	case allValues(values, isJSON):
+		return models.JSON, "data"
+	case allValues(values, isIP):
+		return models.IP, "ip"
+	case allValues(values, isLevel):
+		return models.Level, "level"
+	default:
+		return models.String, ""
+	}
+}
+
+// affixes strips the text every value starts and ends with, up to the innermost
+// punctuation, and returns it
+func affixes(values []string) (string, string) {
+	prefix, suffix := values[0], values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+		for !strings.HasSuffix(value, suffix) {
+			suffix = suffix[1:]
+		}
+	}
+	prefix = prefix[:strings.LastIndexFunc(prefix, isPunct)+1]
+	if i := strings.IndexFunc(suffix, isPunct); i >= 0 {
+		suffix = suffix[i:]
+	} else {
+		suffix = ""
+	}
+
+	// Keep at least one character in every value
+	for i, value := range values {
+		if len(prefix)+len(suffix) >= len(value) {
+			return "", ""
+		}
+		values[i] = value[len(prefix) : len(value)-len(suffix)]
+	}
+	return prefix, suffix
+}
+
+// isPunct checks whether a character is punctuation that may surround a value
+func isPunct(r rune) bool {
+	return strings.ContainsRune("-=>:.#*~!", r)
+}
+
+// constant checks whether all values are equal
+func constant(values []string) bool {
+	for _, value := range values {
+		if value != values[0] {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+// sameLength checks whether all values have the same length
+func sameLength(values []string) bool {
+	for _, value := range values {
+		if len(value) != len(values[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// allValues checks whether all values satisfy a predicate
+func allValues(values []string, predicate func(string) bool) bool {
+	for _, value := range values {
+		if !predicate(value) {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+func isTimestamp(value string) bool {
+	_, err := models.ParseTimestamp(value)
+	return err == nil
+}
+
+func isJSON(value string) bool {
+	return strings.HasPrefix(value, "{") && json.Valid([]byte(value))
+}
+
+func isIP(value string) bool {
+	return net.ParseIP(value) != nil
+}
+
+func isLevel(value string) bool {
+	return levels[strings.ToLower(value)]
+}
@@ -0,0 +1,120 @@
+package infer
+
+import (
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []string
+		wantSource string
+		wantTarget string
+	}{
+		{
+			name: "Level and component",
+			lines: []string{
+				"2024-01-02 10:00:01 INFO [main] started server on 8080",
+				"2024-01-02 10:00:02 WARN [db] slow query took 1200ms",
+				"2024-01-02 10:00:03 ERROR [http] request failed",
+			},
+			wantSource: "@timestamp-timestamp{19}@ @level-level@ [@field1-string@] @field2-string@ @message-string@",
+			wantTarget: "@timestamp@ | @level@ | @field1@ | @field2@ | @message@",
+		},
+		{
+			name: "Constant literals",
+			lines: []string{
+				"5|api  | Server listening on port 3000",
+				"12|worker | Job 42 done",
+				"7|cron | tick",
+			},
+			wantSource: "@field1-number@|@field2-string@  | @message-string@",
+			wantTarget: "@field1@ | @field2@ | @message@",
+		},
+		{
+			name: "Timestamp, ip and number",
+			lines: []string{
+				"2024-01-02T10:00:01Z 10.0.0.1 GET 200",
+				"2024-01-02T10:00:02Z 10.0.0.2 POST 201",
+				"2024-01-02T10:00:03Z 10.0.0.3 GET 404",
+			},
+			wantSource: "@timestamp-timestamp@ @ip-ip@ @field1-string@ @field2-number@",
+			wantTarget: "@timestamp@ | @ip@ | @field1@ | @field2@",
+		},
+		{
+			name: "JSON payload",
+			lines: []string{
+				`INFO request {"path": "/", "status": 200}`,
+				`WARN request {"path": "/login", "status": 401}`,
+			},
+			wantSource: "@level-level@ request @data-json@",
+			wantTarget: "@level@ | @data@",
+		},
+		{
+			name: "Common punctuation",
+			lines: []string{
+				"user=alice action=login",
+				"user=bob action=logout",
+			},
+			wantSource: "user=@field1-string@ action=@field2-string@",
+			wantTarget: "@field1@ | @field2@",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Infer(tt.lines)
+			if err != nil {
+				t.Fatalf("Infer() error = %v", err)
+			}
+
+			if result.Literals.Source != tt.wantSource {
+				t.Errorf("Infer() source = %q, want %q", result.Literals.Source, tt.wantSource)
+			}
+			if result.Literals.Target != tt.wantTarget {
+				t.Errorf("Infer() target = %q, want %q", result.Literals.Target, tt.wantTarget)
+			}
+			if result.MatchRate() != 1 {
+				t.Errorf("Infer() match rate = %v, want 1", result.MatchRate())
+			}
+		})
+	}
+}
+
+func TestInfer_MatchRate(t *testing.T) {
+	lines := []string{
+		"10:00:01 INFO started",
+		"10:00:02 INFO listening",
+		"10:00:03 WARN slow",
+		"10:00:04 INFO ready",
+		"panic",
+	}
+
+	result, err := Infer(lines)
+	if err != nil {
+		t.Fatalf("Infer() error = %v", err)
+	}
+	if result.Lines != 5 || result.Matched != 4 {
+		t.Errorf("Infer() matched %d of %d lines, want 4 of 5", result.Matched, result.Lines)
+	}
+}
+
+func TestInfer_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{name: "Empty", lines: []string{"", "  "}},
+		{name: "Only delimiters", lines: []string{"|||", "(( ))", "|||"}},
+		{name: "Single line", lines: []string{"INFO server started"}},
+		{name: "Identical lines", lines: []string{"INFO ready", "INFO ready"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Infer(tt.lines); err == nil {
+				t.Errorf("Infer() error = nil, want error")
+			}
+		})
+	}
+}
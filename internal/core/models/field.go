@@ -4,7 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/gitKashish/golog/internal/core/stacktrace"
 )
 
 // FieldType represents the data type stored in a field
@@ -14,8 +20,13 @@ type FieldType int
 
 // Field represents a field in a log entry
 type Field struct {
-	Name string
-	Type FieldType
+	Name    string
+	Type    FieldType
+	Width   int       // Number of characters of a fixed-width field, 0 if not fixed
+	Decoded FieldType // Type of the decoded value of a decoding field, like JSON for base64+json
+
+	// Paths of frames folded in stacktrace values, stacktrace.DefaultVendorPaths if nil
+	VendorPaths []string
 }
 
 const (
@@ -24,20 +35,46 @@ const (
 	String
 	Timestamp
 	JSON
+	KV
+	Level
+	IP
+	Duration
+	Bytes
+	Bool
+	UUID
+	URL
+	Hex
+	Base64
+	URLEncoded
+	Quoted
+	Stacktrace
 )
 
 var fieldTypeMap = map[string]FieldType{
-	"raw":       Raw,
-	"number":    Number,
-	"string":    String,
-	"timestamp": Timestamp,
-	"json":      JSON,
+	"raw":        Raw,
+	"number":     Number,
+	"string":     String,
+	"timestamp":  Timestamp,
+	"json":       JSON,
+	"kv":         KV,
+	"level":      Level,
+	"ip":         IP,
+	"duration":   Duration,
+	"bytes":      Bytes,
+	"bool":       Bool,
+	"uuid":       UUID,
+	"url":        URL,
+	"hex":        Hex,
+	"base64":     Base64,
+	"urlencoded": URLEncoded,
+	"quoted":     Quoted,
+	"stacktrace": Stacktrace,
 }
 
 // String returns the string representation of a FieldType
 func (fieldType FieldType) String() string {
 	// Map field type names to corresponding FieldType
-	names := [...]string{"raw", "number", "string", "timestamp", "json"}
+	names := [...]string{"raw", "number", "string", "timestamp", "json", "kv", "level", "ip", "duration", "bytes", "bool", "uuid", "url", "hex", "base64", "urlencoded", "quoted", "stacktrace"}
 
 	// Return "unknown" if fieldType does not have corresponding name
 	if fieldType < 0 || int(fieldType) >= len(names) {
@@ -46,6 +83,28 @@ func (fieldType FieldType) String() string {
 	return names[fieldType]
 }
 
+// HasSubFields checks whether values of the field type hold named parts that
+// are addressable as sub-fields like "details.code"
+func (fieldType FieldType) HasSubFields() bool {
+	return fieldType == JSON || fieldType == KV || fieldType == URL || fieldType == Stacktrace
+}
+
+// Decodes checks whether values of the field type are encoded, like base64,
+// and decoded before being formatted
+func (fieldType FieldType) Decodes() bool {
+	return fieldType == Base64 || fieldType == URLEncoded || fieldType == Quoted
+}
+
+// FieldTypeNames returns the names of all field types in alphabetical order
+func FieldTypeNames() []string {
+	names := make([]string, 0, len(fieldTypeMap))
+	for name := range fieldTypeMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // FieldTypeFromString converts a string to a FieldType
 func FieldTypeFromString(name string) (FieldType, error) {
 	fieldType, ok := fieldTypeMap[name]
@@ -55,27 +114,199 @@ func FieldTypeFromString(name string) (FieldType, error) {
 	return fieldType, nil
 }
 
-// Format formats a field value based on its type
+// ParseTypeName parses the type of a field like "json", or a decoding type
+// followed by the type of the decoded value like "base64+json"
+func ParseTypeName(name string) (FieldType, FieldType, error) {
+	typeName, decodedName, chained := strings.Cut(name, "+")
+	fieldType, err := FieldTypeFromString(typeName)
+	if err != nil || !chained {
+		return fieldType, Raw, err
+	}
+	if !fieldType.Decodes() {
+		return fieldType, Raw, fmt.Errorf("field type `%s` has no decoded value to chain `%s` to", typeName, decodedName)
+	}
+	decoded, err := FieldTypeFromString(decodedName)
+	if err != nil {
+		return fieldType, Raw, err
+	}
+	if decoded.Decodes() {
+		return fieldType, Raw, fmt.Errorf("decoded values can't be decoded again as `%s`", decodedName)
+	}
+	return fieldType, decoded, nil
+}
+
+// TypeName returns the type of a field as written in templates, like "base64+json"
+func (field *Field) TypeName() string {
+	if field.Type.Decodes() && field.Decoded != Raw {
+		return field.Type.String() + "+" + field.Decoded.String()
+	}
+	return field.Type.String()
+}
+
+// HasSubFields checks whether values of the field, or their decoded value for
+// decoding fields like base64+json, hold addressable named parts
+func (field *Field) HasSubFields() bool {
+	return field.Type.HasSubFields() || (field.Type.Decodes() && field.Decoded.HasSubFields())
+}
+
+// Decode decodes the value of a field of a decoding type
+func (field *Field) Decode(value string) (string, error) {
+	switch field.Type {
+	case Base64:
+		return DecodeBase64(value)
+	case URLEncoded:
+		return DecodeURLEncoded(value)
+	case Quoted:
+		return DecodeQuoted(value)
+	}
+	return value, nil
+}
+
+// timestampLayouts lists the layouts timestamps are recognized in.
+// Fractional seconds are accepted after the seconds of any layout.
+var timestampLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"02/Jan/2006:15:04:05 -0700",
+	time.ANSIC,
+	time.UnixDate,
+	time.Stamp,
+	"2006-01-02",
+}
+
+// ParseTimestamp parses a timestamp in any of the commonly used layouts
+func ParseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp `%s`", value)
+}
+
+// Format formats a field value based on its type. Values that can't be
+// coerced into the type are returned as is. Values of decoding fields are
+// formatted by the type of the decoded value once decoded, and returned as is
+// if they can't be decoded.
 func (field *Field) Format(value string) string {
+	if field.Type.Decodes() {
+		decoded, err := field.Decode(value)
+		if err != nil {
+			return value
+		}
+		return (&Field{Name: field.Name, Type: field.Decoded, VendorPaths: field.VendorPaths}).Format(decoded)
+	}
+
+	formatted, err := field.Coerce(value)
+	if err != nil {
+		return value
+	}
+	return formatted
+}
+
+// Coerce formats a field value based on its type, failing if the value is not
+// valid for the type
+func (field *Field) Coerce(value string) (string, error) {
 	switch field.Type {
 	case Number:
-		return value
+		if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+			return value, fmt.Errorf("invalid number `%s`", value)
+		}
+		return value, nil
 	case String:
-		return value
+		return value, nil
 	case Timestamp:
-		formattedTime, err := time.Parse(time.RFC1123Z, value)
-		if err != nil {
-			return value
+		if _, err := ParseTimestamp(value); err != nil {
+			return value, err
+		}
+		// RFC 1123 timestamps are written in full, timestamps in other
+		// recognized layouts are kept as written
+		if formattedTime, err := time.Parse(time.RFC1123Z, value); err == nil {
+			return formattedTime.String(), nil
 		}
-		return formattedTime.String()
+		return value, nil
 	case JSON:
 		formattedJson := bytes.Buffer{}
 		err := json.Indent(&formattedJson, []byte(value), "", "  ")
 		if err != nil {
-			return value
+			return value, fmt.Errorf("invalid JSON `%s`", value)
+		}
+		return formattedJson.String(), nil
+	case Level:
+		severity, ok := ParseSeverity(value)
+		if !ok {
+			return value, fmt.Errorf("invalid level `%s`", value)
+		}
+		return severity.String(), nil
+	case IP:
+		addr, err := netip.ParseAddr(strings.TrimSpace(value))
+		if err != nil {
+			return value, fmt.Errorf("invalid IP address `%s`", value)
 		}
-		return formattedJson.String()
+		return addr.String(), nil
+	case Duration:
+		duration, err := ParseDuration(value)
+		if err != nil {
+			return value, err
+		}
+		return FormatDuration(duration), nil
+	case Bytes:
+		size, err := ParseBytes(value)
+		if err != nil {
+			return value, err
+		}
+		return FormatBytes(size), nil
+	case Bool:
+		parsed, err := ParseBool(value)
+		if err != nil {
+			return value, err
+		}
+		return strconv.FormatBool(parsed), nil
+	case UUID:
+		if !uuidRegex.MatchString(strings.TrimSpace(value)) {
+			return value, fmt.Errorf("invalid UUID `%s`", value)
+		}
+		return strings.ToLower(strings.TrimSpace(value)), nil
+	case URL:
+		if _, err := ParseURL(value); err != nil {
+			return value, err
+		}
+		return value, nil
+	case Hex:
+		match := hexRegex.FindStringSubmatch(strings.TrimSpace(value))
+		if match == nil {
+			return value, fmt.Errorf("invalid hex `%s`", value)
+		}
+		return "0x" + strings.ToLower(match[1]), nil
+	case Stacktrace:
+		// Text without recognized frames, like the first line of a trace
+		// before its continuation lines are appended, is kept as is
+		trace, ok := stacktrace.Parse(value)
+		if !ok {
+			return value, nil
+		}
+		vendorPaths := field.VendorPaths
+		if vendorPaths == nil {
+			vendorPaths = stacktrace.DefaultVendorPaths
+		}
+		return trace.Render(vendorPaths), nil
+	case Base64, URLEncoded, Quoted:
+		decoded, err := field.Decode(value)
+		if err != nil {
+			return value, err
+		}
+		formatted, err := (&Field{Name: field.Name, Type: field.Decoded, VendorPaths: field.VendorPaths}).Coerce(decoded)
+		if err != nil {
+			return value, err
+		}
+		return formatted, nil
 	default:
-		return value
+		return value, nil
 	}
 }
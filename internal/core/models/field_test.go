@@ -3,6 +3,7 @@ package models
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFieldTypeFromString(t *testing.T) {
@@ -42,6 +43,12 @@ func TestFieldTypeFromString(t *testing.T) {
 			want:     JSON,
 			wantErr:  false,
 		},
+		{
+			name:     "KV type",
+			typeName: "kv",
+			want:     KV,
+			wantErr:  false,
+		},
 		{
 			name:     "Invalid type",
 			typeName: "invalid",
@@ -64,6 +71,40 @@ func TestFieldTypeFromString(t *testing.T) {
 	}
 }
 
+func TestParseTypeName(t *testing.T) {
+	tests := []struct {
+		name        string
+		want        FieldType
+		wantDecoded FieldType
+		wantErr     bool
+	}{
+		{name: "json", want: JSON, wantDecoded: Raw},
+		{name: "base64", want: Base64, wantDecoded: Raw},
+		{name: "base64+json", want: Base64, wantDecoded: JSON},
+		{name: "urlencoded+kv", want: URLEncoded, wantDecoded: KV},
+		{name: "quoted+string", want: Quoted, wantDecoded: String},
+		{name: "json+base64", wantErr: true},
+		{name: "base64+quoted", wantErr: true},
+		{name: "base64+jsno", wantErr: true},
+		{name: "base64+", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, decoded, err := ParseTypeName(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTypeName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got != tt.want || decoded != tt.wantDecoded) {
+				t.Errorf("ParseTypeName() = %v, %v, want %v, %v", got, decoded, tt.want, tt.wantDecoded)
+			}
+			if err == nil && (&Field{Type: got, Decoded: decoded}).TypeName() != tt.name {
+				t.Errorf("Field.TypeName() = %q, want %q", (&Field{Type: got, Decoded: decoded}).TypeName(), tt.name)
+			}
+		})
+	}
+}
+
 func TestFieldType_String(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -95,6 +136,11 @@ func TestFieldType_String(t *testing.T) {
 			fieldType: JSON,
 			want:      "json",
 		},
+		{
+			name:      "KV type",
+			fieldType: KV,
+			want:      "kv",
+		},
 		{
 			name:      "Invalid type",
 			fieldType: -1,
@@ -165,6 +211,24 @@ func TestField_Format(t *testing.T) {
 			value: "invalid json",
 			want:  "invalid json",
 		},
+		{
+			name:  "Format base64 JSON value",
+			field: &Field{Name: "test", Type: Base64, Decoded: JSON},
+			value: "eyJpZCI6MX0=",
+			want:  "{\n  \"id\": 1\n}",
+		},
+		{
+			name:  "Format base64 value that is not JSON",
+			field: &Field{Name: "test", Type: Base64, Decoded: JSON},
+			value: "aGVsbG8=",
+			want:  "hello",
+		},
+		{
+			name:  "Format invalid base64 value",
+			field: &Field{Name: "test", Type: Base64, Decoded: JSON},
+			value: "e30!",
+			want:  "e30!",
+		},
 	}
 
 	for _, tt := range tests {
@@ -187,3 +251,101 @@ func TestField_Format(t *testing.T) {
 		})
 	}
 }
+
+func TestField_Coerce(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   *Field
+		value   string
+		want    string // Formatted value, not checked if empty
+		wantErr bool
+	}{
+		{name: "Number", field: &Field{Type: Number}, value: "-1.5e3"},
+		{name: "Invalid number", field: &Field{Type: Number}, value: "12a", wantErr: true},
+		{name: "Timestamp", field: &Field{Type: Timestamp}, value: "Wed, 15 Mar 2023 14:30:45 +0000"},
+		{name: "RFC 3339 timestamp", field: &Field{Type: Timestamp}, value: "2024-01-02T03:04:05Z", want: "2024-01-02T03:04:05Z"},
+		{name: "Common log timestamp", field: &Field{Type: Timestamp}, value: "02/Jan/2024:03:04:05 +0000", want: "02/Jan/2024:03:04:05 +0000"},
+		{name: "Invalid timestamp", field: &Field{Type: Timestamp}, value: "yesterday", wantErr: true},
+		{name: "JSON", field: &Field{Type: JSON}, value: `{"a":1}`},
+		{name: "Invalid JSON", field: &Field{Type: JSON}, value: `{"a":`, wantErr: true},
+		{name: "String", field: &Field{Type: String}, value: "anything"},
+		{name: "Level", field: &Field{Type: Level}, value: "warning"},
+		{name: "Invalid level", field: &Field{Type: Level}, value: "loud", wantErr: true},
+		{name: "IPv4", field: &Field{Type: IP}, value: "10.0.0.1", want: "10.0.0.1"},
+		{name: "IPv6", field: &Field{Type: IP}, value: "2001:DB8::0:1", want: "2001:db8::1"},
+		{name: "Invalid IP", field: &Field{Type: IP}, value: "10.0.0.256", wantErr: true},
+		{name: "Duration", field: &Field{Type: Duration}, value: "1500ms", want: "1.5s"},
+		{name: "Invalid duration", field: &Field{Type: Duration}, value: "soon", wantErr: true},
+		{name: "Bytes", field: &Field{Type: Bytes}, value: "1048576", want: "1 MiB"},
+		{name: "Invalid bytes", field: &Field{Type: Bytes}, value: "12 parsecs", wantErr: true},
+		{name: "Bool", field: &Field{Type: Bool}, value: "Yes", want: "true"},
+		{name: "Invalid bool", field: &Field{Type: Bool}, value: "maybe", wantErr: true},
+		{name: "UUID", field: &Field{Type: UUID}, value: "123E4567-E89B-12D3-A456-426614174000", want: "123e4567-e89b-12d3-a456-426614174000"},
+		{name: "Invalid UUID", field: &Field{Type: UUID}, value: "123e4567", wantErr: true},
+		{name: "URL", field: &Field{Type: URL}, value: "https://example.com/a?b=c", want: "https://example.com/a?b=c"},
+		{name: "Request path", field: &Field{Type: URL}, value: "/users?id=1", want: "/users?id=1"},
+		{name: "Invalid URL", field: &Field{Type: URL}, value: "example", wantErr: true},
+		{name: "Hex", field: &Field{Type: Hex}, value: "0xDEADbeef", want: "0xdeadbeef"},
+		{name: "Hex without prefix", field: &Field{Type: Hex}, value: "ff", want: "0xff"},
+		{name: "Invalid hex", field: &Field{Type: Hex}, value: "0xfg", wantErr: true},
+		{name: "Base64", field: &Field{Type: Base64}, value: "aGVsbG8=", want: "hello"},
+		{name: "Base64 JSON", field: &Field{Type: Base64, Decoded: JSON}, value: "eyJhIjoxfQ", want: "{\n  \"a\": 1\n}"},
+		{name: "Base64 invalid JSON", field: &Field{Type: Base64, Decoded: JSON}, value: "aGVsbG8=", wantErr: true},
+		{name: "Invalid base64", field: &Field{Type: Base64}, value: "not base64!", wantErr: true},
+		{name: "URL-encoded", field: &Field{Type: URLEncoded}, value: "a%3Db+c", want: "a=b c"},
+		{name: "Invalid URL-encoded", field: &Field{Type: URLEncoded}, value: "100%", wantErr: true},
+		{name: "Quoted", field: &Field{Type: Quoted}, value: `"a\n\"b\""`, want: "a\n\"b\""},
+		{name: "Quoted without quotes", field: &Field{Type: Quoted}, value: `tab\there`, want: "tab\there"},
+		{name: "Invalid quoted", field: &Field{Type: Quoted}, value: `bad\q`, wantErr: true},
+		{name: "Stack trace", field: &Field{Type: Stacktrace}, value: "Error: x\n\tat f (/app/a.js:1:2)", want: "Error: x\n    at f (/app/a.js:1:2)"},
+		{name: "Stack trace without vendor paths", field: &Field{Type: Stacktrace, VendorPaths: []string{}}, value: "Error: x\n\tat f (/node_modules/a.js:1:2)", want: "Error: x\n    at f (/node_modules/a.js:1:2)"},
+		{name: "Text without frames", field: &Field{Type: Stacktrace}, value: "panic: boom", want: "panic: boom"},
+		{name: "Quoted stack trace", field: &Field{Type: Quoted, Decoded: Stacktrace}, value: `"Error: x\n  at f (/app/a.js:1:2)"`, want: "Error: x\n    at f (/app/a.js:1:2)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted, err := tt.field.Coerce(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Field.Coerce() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && formatted != tt.value {
+				t.Errorf("Field.Coerce() = %q, want the value as is on error", formatted)
+			}
+			if err == nil && tt.want != "" && formatted != tt.want {
+				t.Errorf("Field.Coerce() = %q, want %q", formatted, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "2023-03-15T14:30:45Z", want: "2023-03-15T14:30:45Z"},
+		{value: "2023-03-15T14:30:45.123+01:00", want: "2023-03-15T13:30:45.123Z"},
+		{value: "Wed, 15 Mar 2023 14:30:45 +0000", want: "2023-03-15T14:30:45Z"},
+		{value: "2025-01-24 07:29:52.954", want: "2025-01-24T07:29:52.954Z"},
+		{value: "2023-03-15 14:30:45,123", want: "2023-03-15T14:30:45.123Z"},
+		{value: "2009/11/10 23:00:00", want: "2009-11-10T23:00:00Z"},
+		{value: "10/Oct/2000:13:55:36 -0700", want: "2000-10-10T20:55:36Z"},
+		{value: "2023-03-15", want: "2023-03-15T00:00:00Z"},
+		{value: "not a timestamp", wantErr: true},
+		{value: "1234", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTimestamp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.UTC().Format(time.RFC3339Nano) != tt.want {
+				t.Errorf("ParseTimestamp() = %v, want %v", got.UTC().Format(time.RFC3339Nano), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Severity is the level of a log entry on an ordered scale, as held by fields
+// of the level type
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityTrace
+	SeverityDebug
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityFatal
+)
+
+// LevelFields are the names of fields holding the level of an entry, checked
+// when no field has the level type
+var LevelFields = []string{"level", "severity", "lvl", "loglevel"}
+
+// levelNames maps the ways services spell a level to its severity
+var levelNames = map[string]Severity{
+	"t": SeverityTrace, "trc": SeverityTrace, "trace": SeverityTrace, "verbose": SeverityTrace, "finest": SeverityTrace,
+	"d": SeverityDebug, "dbg": SeverityDebug, "debug": SeverityDebug, "fine": SeverityDebug,
+	"i": SeverityInfo, "inf": SeverityInfo, "info": SeverityInfo, "information": SeverityInfo, "notice": SeverityInfo,
+	"w": SeverityWarn, "wrn": SeverityWarn, "warn": SeverityWarn, "warning": SeverityWarn,
+	"e": SeverityError, "err": SeverityError, "error": SeverityError, "severe": SeverityError,
+	"f": SeverityFatal, "ftl": SeverityFatal, "fatal": SeverityFatal, "c": SeverityFatal, "crit": SeverityFatal, "critical": SeverityFatal,
+	"panic": SeverityFatal, "alert": SeverityFatal, "emerg": SeverityFatal, "emergency": SeverityFatal,
+}
+
+// syslogLevels maps syslog levels, from 0 for emergency to 7 for debug, to their severity
+var syslogLevels = [...]Severity{SeverityFatal, SeverityFatal, SeverityFatal, SeverityError, SeverityWarn, SeverityInfo, SeverityInfo, SeverityDebug}
+
+// String returns the canonical name of a severity like "WARN"
+func (severity Severity) String() string {
+	names := [...]string{"UNKNOWN", "TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+	if severity < 0 || int(severity) >= len(names) {
+		return "UNKNOWN"
+	}
+	return names[severity]
+}
+
+// Severities returns the known severities from lowest to highest
+func Severities() []Severity {
+	return []Severity{SeverityTrace, SeverityDebug, SeverityInfo, SeverityWarn, SeverityError, SeverityFatal}
+}
+
+// ParseSeverity parses the way a service spells a level, like "E", "err",
+// "ERROR", 50 for pino or 3 for syslog
+func ParseSeverity(value string) (Severity, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if severity, ok := levelNames[value]; ok {
+		return severity, true
+	}
+
+	number, err := strconv.Atoi(value)
+	switch {
+	case err != nil || number < 0:
+		return SeverityUnknown, false
+	case number < len(syslogLevels):
+		return syslogLevels[number], true
+	case number >= 10:
+		// pino levels go from 10 for trace to 60 for fatal in steps of 10
+		return min(SeverityTrace+Severity(number/10-1), SeverityFatal), true
+	}
+	return SeverityUnknown, false
+}
+
+// Level returns the level of a record as written, held by its first field of
+// the level type or else by a field named like one of LevelFields
+func (r *Record) Level() (string, bool) {
+	for _, name := range r.names {
+		if r.fields[name].Type == Level {
+			return r.values[name], true
+		}
+	}
+	for _, name := range LevelFields {
+		if value, ok := r.values[name]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Severity returns the severity of a record from its level, unknown if it has
+// none or the level isn't recognized
+func (r *Record) Severity() (Severity, bool) {
+	value, ok := r.Level()
+	if !ok {
+		return SeverityUnknown, false
+	}
+	return ParseSeverity(value)
+}
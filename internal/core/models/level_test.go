@@ -0,0 +1,88 @@
+package models
+
+import "testing"
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Severity
+		wantOk bool
+	}{
+		{value: "E", want: SeverityError, wantOk: true},
+		{value: "err", want: SeverityError, wantOk: true},
+		{value: " ERROR ", want: SeverityError, wantOk: true},
+		{value: "fatal", want: SeverityFatal, wantOk: true},
+		{value: "Warning", want: SeverityWarn, wantOk: true},
+		{value: "notice", want: SeverityInfo, wantOk: true},
+		{value: "10", want: SeverityTrace, wantOk: true},
+		{value: "30", want: SeverityInfo, wantOk: true},
+		{value: "50", want: SeverityError, wantOk: true},
+		{value: "60", want: SeverityFatal, wantOk: true},
+		{value: "70", want: SeverityFatal, wantOk: true},
+		{value: "0", want: SeverityFatal, wantOk: true},
+		{value: "3", want: SeverityError, wantOk: true},
+		{value: "4", want: SeverityWarn, wantOk: true},
+		{value: "7", want: SeverityDebug, wantOk: true},
+		{value: "8", want: SeverityUnknown},
+		{value: "-1", want: SeverityUnknown},
+		{value: "loud", want: SeverityUnknown},
+		{value: "", want: SeverityUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseSeverity(tt.value)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ParseSeverity(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRecord_Severity(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []*Field
+		values []string
+		want   Severity
+		wantOk bool
+	}{
+		{
+			name:   "Level type",
+			fields: []*Field{{Name: "code", Type: String}, {Name: "sev", Type: Level}},
+			values: []string{"E42", "W"},
+			want:   SeverityWarn, wantOk: true,
+		},
+		{
+			name:   "Level field name",
+			fields: []*Field{{Name: "severity", Type: String}},
+			values: []string{"error"},
+			want:   SeverityError, wantOk: true,
+		},
+		{
+			name:   "Level type before field name",
+			fields: []*Field{{Name: "level", Type: String}, {Name: "sev", Type: Level}},
+			values: []string{"error", "info"},
+			want:   SeverityInfo, wantOk: true,
+		},
+		{
+			name:   "No level",
+			fields: []*Field{{Name: "message", Type: String}},
+			values: []string{"error"},
+			want:   SeverityUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := NewRecord(1, "")
+			for i, field := range tt.fields {
+				record.Set(field, tt.values[i])
+			}
+			got, ok := record.Severity()
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Record.Severity() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
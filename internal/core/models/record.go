@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Record represents a single log entry after it has been parsed
+type Record struct {
+	Line    int    // Line number of the entry in the source (1-based, 0 if unknown)
+	Raw     string // Original log entry
+	Output  string // Formatted log entry
+	Matched bool   // Whether the entry matched the source template
+
+	names  []string          // Field names in the order they were captured
+	fields map[string]*Field // Field definitions by name
+	values map[string]string // Raw field values by name
+}
+
+// NewRecord creates a new empty record for a raw log entry
+func NewRecord(line int, raw string) *Record {
+	return &Record{
+		Line:   line,
+		Raw:    raw,
+		Output: raw,
+		fields: make(map[string]*Field),
+		values: make(map[string]string),
+	}
+}
+
+// Set stores the raw value captured for a field
+func (r *Record) Set(field *Field, value string) {
+	if _, ok := r.fields[field.Name]; !ok {
+		r.names = append(r.names, field.Name)
+	}
+	r.fields[field.Name] = field
+	r.values[field.Name] = value
+}
+
+// Value returns the raw value of a field
+func (r *Record) Value(name string) (string, bool) {
+	value, ok := r.values[name]
+	return value, ok
+}
+
+// Field returns the definition of a field
+func (r *Record) Field(name string) (*Field, bool) {
+	field, ok := r.fields[name]
+	return field, ok
+}
+
+// Formatted returns the value of a field formatted according to its type
+func (r *Record) Formatted(name string) (string, bool) {
+	value, ok := r.values[name]
+	if !ok {
+		return "", false
+	}
+	return r.fields[name].Format(value), true
+}
+
+// Names returns the names of all fields in the record in capture order
+func (r *Record) Names() []string {
+	return r.names
+}
+
+// Time returns the time of a record read from the named field, or from the first
+// timestamp field if name is empty. It is false if the value is not a timestamp.
+func (r *Record) Time(name string) (time.Time, bool) {
+	if name == "" {
+		for _, fieldName := range r.names {
+			if r.fields[fieldName].Type == Timestamp {
+				name = fieldName
+				break
+			}
+		}
+	}
+
+	value, ok := r.values[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := ParseTimestamp(value)
+	return t, err == nil
+}
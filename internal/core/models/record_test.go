@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecord_Time(t *testing.T) {
+	record := NewRecord(1, "")
+	record.Set(&Field{Name: "level", Type: String}, "INFO")
+	record.Set(&Field{Name: "ts", Type: Timestamp}, "2024-01-02T10:00:00Z")
+	record.Set(&Field{Name: "seen", Type: String}, "2024-01-03 08:30:00")
+
+	want := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		field  string
+		want   time.Time
+		wantOk bool
+	}{
+		{name: "First timestamp field", field: "", want: want, wantOk: true},
+		{name: "Named field", field: "seen", want: time.Date(2024, 1, 3, 8, 30, 0, 0, time.UTC), wantOk: true},
+		{name: "Not a timestamp", field: "level", wantOk: false},
+		{name: "Missing field", field: "time", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := record.Time(tt.field)
+			if ok != tt.wantOk {
+				t.Fatalf("Record.Time() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("Record.Time() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
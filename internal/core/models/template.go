@@ -6,8 +6,74 @@ import (
 
 // TemplateLiterals store the template literals representing the source and target log formats
 type TemplateLiterals struct {
-	Source string `yaml:"sourceTemplate"`
-	Target string `yaml:"targetTemplate"`
+	Extends   string         `yaml:"extends"`     // Name of a built-in preset providing defaults
+	Format    string         `yaml:"inputFormat"` // Input format, defaults to FormatTemplate
+	Source    string         `yaml:"sourceTemplate"`
+	Target    string         `yaml:"targetTemplate"`
+	Delimiter string         `yaml:"delimiter"` // Column delimiter of delimited input
+	Columns   []string       `yaml:"columns"`   // Columns of delimited input as "name-type"
+	Header    *bool          `yaml:"header"`    // Whether delimited input starts with a header row, defaults to true
+	Trim      string         `yaml:"trim"`      // Padding removed from fixed-width fields, defaults to TrimBoth
+	Tests     []TemplateTest `yaml:"tests"`     // Sample lines and the output expected from them
+
+	OnMismatch string `yaml:"onMismatch"`       // What happens to entries that don't match, defaults to MismatchPassthrough
+	Marker     string `yaml:"mismatchMarker"`   // Prefix of marked entries, defaults to DefaultMarker
+	Fallback   string `yaml:"fallbackTemplate"` // Target template of marked entries, using the @raw@ and @line@ fields
+
+	StrictTypes bool `yaml:"strictTypes"` // Whether entries with values their field type rejects are unmatched
+}
+
+// TemplateTest is a sample of input and the output the template is expected to render
+type TemplateTest struct {
+	Name   string            `yaml:"name"`
+	Input  string            `yaml:"input"`  // Input lines
+	Output string            `yaml:"output"` // Expected output, one rendered record per line
+	Fields map[string]string `yaml:"fields"` // Expected values of fields of the first record
+}
+
+// Trim modes of fixed-width fields
+const (
+	TrimBoth  = "both"  // Remove padding on both sides
+	TrimLeft  = "left"  // Remove leading padding
+	TrimRight = "right" // Remove trailing padding
+	TrimNone  = "none"  // Keep values as they are
+)
+
+// Actions taken on entries that don't match the template
+const (
+	MismatchPassthrough = "passthrough"        // Output the entry as is
+	MismatchDrop        = "drop"               // Leave the entry out
+	MismatchAppend      = "append-to-previous" // Append the entry to the previous one, like a continuation line
+	MismatchMark        = "mark"               // Output the entry with a marker or through the fallback template
+	MismatchReject      = "reject"             // Leave the entry out and write it to a rejects file
+)
+
+// DefaultMarker is the prefix of marked entries without a fallback template
+const DefaultMarker = "[unmatched] "
+
+// Input formats of log entries
+const (
+	FormatTemplate  = "template"  // Entries are parsed with the source template
+	FormatJSON      = "json"      // Entries are JSON objects whose keys become fields
+	FormatLogfmt    = "logfmt"    // Entries are key=value pairs whose keys become fields
+	FormatCSV       = "csv"       // Entries are comma separated rows whose columns become fields
+	FormatTSV       = "tsv"       // Entries are tab separated rows whose columns become fields
+	FormatDelimited = "delimited" // Entries are rows separated by a custom delimiter
+)
+
+// IsSourceless checks whether entries of an input format are parsed without a source template
+func IsSourceless(format string) bool {
+	return format == FormatJSON || format == FormatLogfmt || IsDelimited(format)
+}
+
+// IsDelimited checks whether an input format holds rows of delimited columns
+func IsDelimited(format string) bool {
+	return format == FormatCSV || format == FormatTSV || format == FormatDelimited
+}
+
+// HasHeader checks whether delimited input starts with a header row
+func (l TemplateLiterals) HasHeader() bool {
+	return l.Header == nil || *l.Header
 }
 
 // Template represents a template for parsing log entries
@@ -18,6 +84,7 @@ type Template struct {
 	TargetFieldRegex *regexp.Regexp
 	FieldNames       map[string]bool
 	Fields           []*Field // List of fields
+	Delimiter        rune     // Column delimiter of delimited input
 }
 
 // NewTemplate creates a new empty template
@@ -27,3 +94,13 @@ func NewTemplate() *Template {
 		Fields:     []*Field{},
 	}
 }
+
+// Field returns the field of the source template with the given name
+func (t *Template) Field(name string) *Field {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
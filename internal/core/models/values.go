@@ -0,0 +1,175 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	uuidRegex  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hexRegex   = regexp.MustCompile(`^(?:0[xX])?([0-9a-fA-F]+)$`)
+	bytesRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-zA-Z]*)$`)
+)
+
+// byteUnits maps size units to their multiplier, decimal for KB and binary
+// for KiB and K
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1e3,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1e6,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1e9,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1e12,
+	"tib": 1 << 40,
+}
+
+// binaryUnits are the units sizes are formatted with
+var binaryUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+// boolValues maps the spellings of booleans to their value
+var boolValues = map[string]bool{
+	"true": true, "t": true, "yes": true, "y": true, "on": true, "1": true,
+	"false": false, "f": false, "no": false, "n": false, "off": false, "0": false,
+}
+
+// ParseDuration parses a duration like "1.5s", "1500ms" or "2h45m"
+func ParseDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration `%s`", value)
+	}
+	return duration, nil
+}
+
+// FormatDuration formats a duration for humans, like "1.5s" or "1h 2m 3s".
+// Durations of a second or more are rounded to the millisecond.
+func FormatDuration(duration time.Duration) string {
+	if duration > -time.Second && duration < time.Second {
+		return duration.String()
+	}
+
+	sign := ""
+	if duration < 0 {
+		sign = "-"
+		duration = -duration
+	}
+	duration = duration.Round(time.Millisecond)
+
+	parts := []string{}
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}} {
+		if count := duration / unit.size; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", count, unit.suffix))
+			duration -= count * unit.size
+		}
+	}
+	if duration > 0 {
+		parts = append(parts, strconv.FormatFloat(duration.Seconds(), 'f', -1, 64)+"s")
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// ParseBytes parses a size in bytes like "1048576", "1.5MB" or "512 KiB"
+func ParseBytes(value string) (int64, error) {
+	match := bytesRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size `%s`", value)
+	}
+	multiplier, ok := byteUnits[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size `%s`", value)
+	}
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size `%s`", value)
+	}
+	return int64(math.Round(number * multiplier)), nil
+}
+
+// FormatBytes formats a size in binary units, like "1 MiB" or "1.5 KiB"
+func FormatBytes(size int64) string {
+	value := float64(size)
+	unit := 0
+	for math.Abs(value) >= 1024 && unit < len(binaryUnits)-1 {
+		value /= 1024
+		unit++
+	}
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64) + " " + binaryUnits[unit]
+}
+
+// ParseBool parses a boolean like "true", "yes", "on" or "1"
+func ParseBool(value string) (bool, error) {
+	parsed, ok := boolValues[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return false, fmt.Errorf("invalid bool `%s`", value)
+	}
+	return parsed, nil
+}
+
+// ParseURL parses an absolute URL like "https://example.com/a?b=c", or a
+// request path like "/a?b=c"
+func ParseURL(value string) (*url.URL, error) {
+	trimmed := strings.TrimSpace(value)
+	parsed, err := url.Parse(trimmed)
+	if err != nil || !((parsed.Scheme != "" && parsed.Host != "") || strings.HasPrefix(trimmed, "/")) {
+		return nil, fmt.Errorf("invalid URL `%s`", value)
+	}
+	return parsed, nil
+}
+
+// base64Encodings are the base64 alphabets tried when decoding, with and
+// without padding
+var base64Encodings = []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding}
+
+// DecodeBase64 decodes base64 text in the standard or URL alphabet, with or
+// without padding. Decoded values that are not text are rejected.
+func DecodeBase64(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	for _, encoding := range base64Encodings {
+		decoded, err := encoding.DecodeString(trimmed)
+		if err == nil && utf8.Valid(decoded) {
+			return string(decoded), nil
+		}
+	}
+	return "", fmt.Errorf("invalid base64 `%s`", value)
+}
+
+// DecodeURLEncoded decodes percent-encoded text like a query string or a form
+// body, where `+` stands for a space
+func DecodeURLEncoded(value string) (string, error) {
+	decoded, err := url.QueryUnescape(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL-encoded text `%s`", value)
+	}
+	return decoded, nil
+}
+
+// DecodeQuoted decodes a Go quoted string like "a\n\"b\"", as written with
+// %q. The surrounding quotes may be left out of the value.
+func DecodeQuoted(value string) (string, error) {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) < 2 || (trimmed[0] != '"' && trimmed[0] != '`') || trimmed[len(trimmed)-1] != trimmed[0] {
+		trimmed = `"` + trimmed + `"`
+	}
+	decoded, err := strconv.Unquote(trimmed)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted string `%s`", value)
+	}
+	return decoded, nil
+}
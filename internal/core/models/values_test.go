@@ -0,0 +1,107 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{duration: 0, want: "0s"},
+		{duration: 250 * time.Microsecond, want: "250µs"},
+		{duration: 1500 * time.Millisecond, want: "1.5s"},
+		{duration: 90 * time.Second, want: "1m 30s"},
+		{duration: 26*time.Hour + 3*time.Minute + 4500*time.Millisecond, want: "1d 2h 3m 4.5s"},
+		{duration: 2*time.Hour + 400*time.Microsecond, want: "2h"},
+		{duration: -90 * time.Second, want: "-1m 30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatDuration(tt.duration); got != tt.want {
+				t.Errorf("FormatDuration() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "1048576", want: 1 << 20},
+		{value: "512B", want: 512},
+		{value: "1.5 KiB", want: 1536},
+		{value: "2K", want: 2048},
+		{value: "1MB", want: 1000000},
+		{value: "3gib", want: 3 << 30},
+		{value: "-1", wantErr: true},
+		{value: "1 parsec", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseBytes(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0 B"},
+		{size: 500, want: "500 B"},
+		{size: 1536, want: "1.5 KiB"},
+		{size: 1 << 20, want: "1 MiB"},
+		{size: 5<<30 + 1<<29, want: "5.5 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatBytes(tt.size); got != tt.want {
+				t.Errorf("FormatBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{value: "true", want: true},
+		{value: "ON", want: true},
+		{value: "1", want: true},
+		{value: "no", want: false},
+		{value: "F", want: false},
+		{value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseBool(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package output
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/pkg/fileutil"
+)
+
+// DefaultMaxOpenFiles is the number of output files kept open when no limit is given
+const DefaultMaxOpenFiles = 64
+
+// placeholderRegex matches field placeholders in a path template
+var placeholderRegex = regexp.MustCompile(`@([\w.]+)@`)
+
+// Options holds configuration for routing records into output files
+type Options struct {
+	// Path template like "out/@module@/@level@.log" resolved for every record
+	PathTemplate string
+	// Maximum number of simultaneously open files, least recently used files are closed first
+	MaxOpenFiles int
+	// Size in bytes after which an output file is rotated, 0 disables rotation
+	MaxSize int64
+	// Path of the file receiving records that do not match or lack a path field
+	UnmatchedPath string
+}
+
+// Router writes records into output files derived from their field values
+type Router struct {
+	options Options
+	open    *list.List               // Open files, most recently used first
+	handles map[string]*list.Element // Open files by path
+	created map[string]bool          // Files created during this run
+	paths   []string                 // Files written in order of creation
+}
+
+// openFile is an output file kept open by the router
+type openFile struct {
+	path string
+	file *os.File
+	size int64
+}
+
+// PathTemplate builds a path template placing records into a directory
+// hierarchy named after the values of the given fields
+func PathTemplate(dir string, fields []string) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = "@" + field + "@"
+	}
+	return filepath.Join(dir, strings.Join(parts, string(filepath.Separator))+".log")
+}
+
+// IsPathTemplate checks whether a path contains field placeholders
+func IsPathTemplate(path string) bool {
+	return placeholderRegex.MatchString(path)
+}
+
+// NewRouter creates a new Router
+func NewRouter(options Options) *Router {
+	if options.MaxOpenFiles <= 0 {
+		options.MaxOpenFiles = DefaultMaxOpenFiles
+	}
+	if options.UnmatchedPath == "" {
+		options.UnmatchedPath = filepath.Join(staticDir(options.PathTemplate), "unmatched.log")
+	}
+	return &Router{
+		options: options,
+		open:    list.New(),
+		handles: make(map[string]*list.Element),
+		created: make(map[string]bool),
+	}
+}
+
+// Write writes the output of a record into the file derived from its fields
+func (r *Router) Write(record *models.Record) error {
+	path, ok := r.resolve(record)
+	if !ok {
+		path = r.options.UnmatchedPath
+	} else if filepath.Clean(path) == filepath.Clean(r.options.UnmatchedPath) {
+		// Records would be mixed up with unmatched lines
+		return fmt.Errorf("output file %s of a record is the file of unmatched lines, write unmatched lines to another path", path)
+	}
+
+	handle, err := r.acquire(path)
+	if err != nil {
+		return err
+	}
+
+	entry := fileutil.Entry(record.Output)
+	if r.options.MaxSize > 0 && handle.size > 0 && handle.size+int64(len(entry)) > r.options.MaxSize {
+		if err := r.rotate(handle); err != nil {
+			return err
+		}
+	}
+
+	n, err := handle.file.WriteString(entry)
+	handle.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+	return nil
+}
+
+// Paths returns the files written so far in order of creation
+func (r *Router) Paths() []string {
+	return r.paths
+}
+
+// Close closes all open files
+func (r *Router) Close() error {
+	var errs []error
+	for element := r.open.Front(); element != nil; element = element.Next() {
+		if err := element.Value.(*openFile).file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	r.open.Init()
+	r.handles = make(map[string]*list.Element)
+	return errors.Join(errs...)
+}
+
+// resolve builds the output path of a record from the path template
+func (r *Router) resolve(record *models.Record) (string, bool) {
+	if !record.Matched {
+		return "", false
+	}
+
+	resolved := true
+	path := placeholderRegex.ReplaceAllStringFunc(r.options.PathTemplate, func(placeholder string) string {
+		value, ok := record.Value(placeholder[1 : len(placeholder)-1])
+		if !ok || strings.TrimSpace(value) == "" {
+			resolved = false
+			return ""
+		}
+		return sanitize(value)
+	})
+	return path, resolved
+}
+
+// acquire returns an open file for the path, closing the least recently used file if needed
+func (r *Router) acquire(path string) (*openFile, error) {
+	if element, ok := r.handles[path]; ok {
+		r.open.MoveToFront(element)
+		return element.Value.(*openFile), nil
+	}
+
+	// Close least recently used file when limit is reached
+	if r.open.Len() >= r.options.MaxOpenFiles {
+		handle := r.open.Back().Value.(*openFile)
+		r.release(handle)
+		if err := handle.file.Close(); err != nil {
+			return nil, fmt.Errorf("error closing file: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating directory: %w", err)
+	}
+
+	// Files are truncated when first created and appended to when reopened
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if !r.created[path] {
+		flags |= os.O_TRUNC
+		r.created[path] = true
+		r.paths = append(r.paths, path)
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("error creating file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error reading file info: %w", err)
+	}
+
+	handle := &openFile{path: path, file: file, size: info.Size()}
+	r.handles[path] = r.open.PushFront(handle)
+	return handle, nil
+}
+
+// release stops keeping a file open, without closing it
+func (r *Router) release(handle *openFile) {
+	if element, ok := r.handles[handle.path]; ok {
+		r.open.Remove(element)
+		delete(r.handles, handle.path)
+	}
+}
+
+// rotate moves a full output file to the next free numbered path and starts a new one.
+// On failure the closed file is released, so it is reopened by the next write.
+func (r *Router) rotate(handle *openFile) error {
+	r.release(handle)
+	if err := handle.file.Close(); err != nil {
+		return fmt.Errorf("error closing file: %w", err)
+	}
+
+	index := 1
+	for fileExists(fmt.Sprintf("%s.%d", handle.path, index)) {
+		index++
+	}
+	if err := os.Rename(handle.path, fmt.Sprintf("%s.%d", handle.path, index)); err != nil {
+		return fmt.Errorf("error rotating file: %w", err)
+	}
+
+	file, err := os.OpenFile(handle.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	r.handles[handle.path] = r.open.PushFront(handle)
+	handle.file = file
+	handle.size = 0
+	return nil
+}
+
+// sanitize makes a field value safe to use as a single path element
+func sanitize(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == 0 || r == '\n' || r == '\r' {
+			return '_'
+		}
+		return r
+	}, value)
+	if value == "." || value == ".." {
+		return "_"
+	}
+	return value
+}
+
+// staticDir returns the directory of a path template preceding its first placeholder
+func staticDir(pathTemplate string) string {
+	location := placeholderRegex.FindStringIndex(pathTemplate)
+	if location == nil {
+		return filepath.Dir(pathTemplate)
+	}
+	prefix := pathTemplate[:location[0]]
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		return filepath.Dir(prefix)
+	}
+	return filepath.Clean(prefix)
+}
+
+// fileExists checks if a file exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,180 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// newRecord creates a matched record with the given string fields
+func newRecord(output string, values map[string]string) *models.Record {
+	record := models.NewRecord(0, output)
+	record.Matched = true
+	for name, value := range values {
+		record.Set(&models.Field{Name: name, Type: models.String}, value)
+	}
+	return record
+}
+
+func TestRouter_Write(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	router := NewRouter(Options{
+		PathTemplate: PathTemplate(tmpDir, []string{"module", "level"}),
+	})
+
+	records := []*models.Record{
+		newRecord("users info", map[string]string{"module": "users", "level": "INFO"}),
+		newRecord("jobs error", map[string]string{"module": "jobs", "level": "ERROR"}),
+		newRecord("users info again", map[string]string{"module": "users", "level": "INFO"}),
+		newRecord("no level", map[string]string{"module": "users"}),
+		models.NewRecord(0, "unmatched line"),
+	}
+	for _, record := range records {
+		if err := router.Write(record); err != nil {
+			t.Fatalf("Router.Write() error = %v", err)
+		}
+	}
+	if err := router.Close(); err != nil {
+		t.Fatalf("Router.Close() error = %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		lines []string
+	}{
+		{path: filepath.Join(tmpDir, "users", "INFO.log"), lines: []string{"users info", "users info again"}},
+		{path: filepath.Join(tmpDir, "jobs", "ERROR.log"), lines: []string{"jobs error"}},
+		{path: filepath.Join(tmpDir, "unmatched.log"), lines: []string{"no level", "unmatched line"}},
+	}
+
+	for _, tt := range tests {
+		content, err := os.ReadFile(tt.path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", tt.path, err)
+		}
+		for _, line := range tt.lines {
+			if !strings.Contains(string(content), line) {
+				t.Errorf("%s = %q, does not contain %q", tt.path, content, line)
+			}
+		}
+	}
+
+	if len(router.Paths()) != len(tests) {
+		t.Errorf("Router.Paths() returned %d paths, want %d", len(router.Paths()), len(tests))
+	}
+}
+
+func TestRouter_MaxOpenFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Only one file may be open, so every switch reopens a file in append mode
+	router := NewRouter(Options{
+		PathTemplate: filepath.Join(tmpDir, "@module@.log"),
+		MaxOpenFiles: 1,
+	})
+
+	for _, module := range []string{"a", "b", "a", "b"} {
+		if err := router.Write(newRecord("line "+module, map[string]string{"module": module})); err != nil {
+			t.Fatalf("Router.Write() error = %v", err)
+		}
+	}
+	if err := router.Close(); err != nil {
+		t.Fatalf("Router.Close() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "a.log"))
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	if got := strings.Count(string(content), "line a"); got != 2 {
+		t.Errorf("a.log contains %d entries, want 2", got)
+	}
+}
+
+func TestRouter_Rotation(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "app.log")
+
+	// Every entry is larger than half the limit, so each write after the first rotates
+	router := NewRouter(Options{
+		PathTemplate: filepath.Join(tmpDir, "@module@.log"),
+		MaxSize:      100,
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := router.Write(newRecord("entry", map[string]string{"module": "app"})); err != nil {
+			t.Fatalf("Router.Write() error = %v", err)
+		}
+	}
+	if err := router.Close(); err != nil {
+		t.Fatalf("Router.Close() error = %v", err)
+	}
+
+	for _, rotated := range []string{path, path + ".1", path + ".2"} {
+		if _, err := os.Stat(rotated); err != nil {
+			t.Errorf("expected rotated file %s: %v", rotated, err)
+		}
+	}
+}
+
+func TestRouter_Rotation_Error(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "app.log")
+	router := NewRouter(Options{
+		PathTemplate: filepath.Join(tmpDir, "@module@.log"),
+		MaxSize:      10,
+	})
+
+	record := newRecord("entry long enough to rotate", map[string]string{"module": "app"})
+	if err := router.Write(record); err != nil {
+		t.Fatalf("Router.Write() error = %v", err)
+	}
+
+	// The file can't be rotated once removed, its closed handle is dropped
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove output: %v", err)
+	}
+	if err := router.Write(record); err == nil {
+		t.Fatalf("Router.Write() error = nil, want rotation error")
+	}
+	if err := router.Write(record); err != nil {
+		t.Errorf("Router.Write() after a failed rotation error = %v", err)
+	}
+	if err := router.Close(); err != nil {
+		t.Errorf("Router.Close() error = %v", err)
+	}
+}
+
+func TestRouter_UnmatchedCollision(t *testing.T) {
+	router := NewRouter(Options{PathTemplate: filepath.Join(t.TempDir(), "@module@.log")})
+	defer router.Close()
+
+	if err := router.Write(models.NewRecord(0, "unmatched line")); err != nil {
+		t.Fatalf("Router.Write() error = %v", err)
+	}
+	if err := router.Write(newRecord("entry", map[string]string{"module": "unmatched"})); err == nil {
+		t.Errorf("Router.Write() error = nil, want error for a record routed to the unmatched file")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "users", want: "users"},
+		{value: "../etc/passwd", want: ".._etc_passwd"},
+		{value: "..", want: "_"},
+		{value: " spaced ", want: "spaced"},
+	}
+
+	for _, tt := range tests {
+		if got := sanitize(tt.value); got != tt.want {
+			t.Errorf("sanitize(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// columnRegex matches a column declaration like "status-number" or "status"
+var columnRegex = regexp.MustCompile(`^(\w+)(?:-(\w+(?:\+\w+)?))?$`)
+
+// headerRegex matches characters of header names that cannot be used in field names
+var headerRegex = regexp.MustCompile(`[^\w]`)
+
+// setupDelimited sets up the columns and delimiter of delimited input
+func (p *TemplateParser) setupDelimited() error {
+	literals := p.template.Literals
+
+	switch literals.Format {
+	case models.FormatCSV:
+		p.template.Delimiter = ','
+	case models.FormatTSV:
+		p.template.Delimiter = '\t'
+	default:
+		delimiter, size := utf8.DecodeRuneInString(literals.Delimiter)
+		if size == 0 || size != len(literals.Delimiter) || delimiter == '"' || delimiter == '\n' {
+			return fmt.Errorf("invalid delimiter `%s`, a single character is required", literals.Delimiter)
+		}
+		p.template.Delimiter = delimiter
+	}
+
+	// Declared columns name the fields, otherwise they are named by the header row
+	for _, column := range literals.Columns {
+		match := columnRegex.FindStringSubmatch(column)
+		if match == nil {
+			return fmt.Errorf("invalid column `%s`, expected `name-type`", column)
+		}
+		if p.template.FieldNames[match[1]] {
+			return fmt.Errorf("duplicate column name `%s`", match[1])
+		}
+
+		fieldType, decoded := models.String, models.Raw
+		if match[2] != "" {
+			var err error
+			if fieldType, decoded, err = models.ParseTypeName(match[2]); err != nil {
+				return err
+			}
+		}
+		p.template.FieldNames[match[1]] = true
+		p.template.Fields = append(p.template.Fields, &models.Field{Name: match[1], Type: fieldType, Decoded: decoded})
+	}
+
+	if len(literals.Columns) == 0 {
+		if !literals.HasHeader() {
+			return fmt.Errorf("columns are required with %s input without header", literals.Format)
+		}
+		return nil
+	}
+	return p.parseTargetTemplate()
+}
+
+// setHeader names the fields after the columns of a header row
+func (p *TemplateParser) setHeader(header []string) error {
+	p.template.Fields = []*models.Field{}
+	p.template.FieldNames = make(map[string]bool)
+
+	for i, column := range header {
+		name := headerRegex.ReplaceAllString(strings.TrimSpace(column), "_")
+		if name == "" {
+			name = fmt.Sprintf("column%d", i+1)
+		}
+		// Repeated column names get a numeric suffix
+		base := name
+		for suffix := 2; p.template.FieldNames[name]; suffix++ {
+			name = fmt.Sprintf("%s_%d", base, suffix)
+		}
+		p.template.FieldNames[name] = true
+		p.template.Fields = append(p.template.Fields, &models.Field{Name: name, Type: models.String})
+	}
+	return p.parseTargetTemplate()
+}
+
+// newCSVReader creates a reader for delimited rows. Lazy readers accept quotes
+// inside unquoted cells and unterminated quoted cells, which then run to the
+// end of the text, so they only read single lines.
+func (p *TemplateParser) newCSVReader(text string, lazy bool) *csv.Reader {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = p.template.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = lazy
+	return reader
+}
+
+// delimitedRecord creates a record from the columns of a row
+func (p *TemplateParser) delimitedRecord(columns []string, raw string) *models.Record {
+	record := models.NewRecord(0, raw)
+	if len(p.template.Fields) == 0 || len(columns) != len(p.template.Fields) {
+		return record
+	}
+
+	for i, field := range p.template.Fields {
+		record.Set(field, columns[i])
+		p.addSubFields(field, columns[i], record)
+	}
+	record.Matched = true
+	return p.checkTypes(record)
+}
+
+// parseDelimitedRecord parses a single row of delimited input
+func (p *TemplateParser) parseDelimitedRecord(sourceLog string) *models.Record {
+	columns, err := p.newCSVReader(sourceLog, true).Read()
+	if err != nil {
+		return models.NewRecord(0, sourceLog)
+	}
+	return p.delimitedRecord(columns, sourceLog)
+}
+
+// parseDelimitedRecords parses rows of delimited input, which may span multiple
+// lines when quoted cells contain line breaks
+func (p *TemplateParser) parseDelimitedRecords(lines []string) ([]*models.Record, error) {
+	records := []*models.Record{}
+	needsHeader := p.template.Literals.HasHeader()
+
+	// row adds a row read from the input, the header row only names the fields
+	row := func(columns []string, raw string, line int) error {
+		if needsHeader {
+			needsHeader = false
+			if len(p.template.Literals.Columns) == 0 {
+				return p.setHeader(columns)
+			}
+			return nil
+		}
+
+		record := p.delimitedRecord(columns, raw)
+		record.Line = line
+		if record.Matched {
+			record.Output = p.Render(record)
+		}
+		records = append(records, record)
+		return nil
+	}
+
+	// Reading restarts after a malformed line, which is kept as an unmatched
+	// record, so an unterminated quote doesn't swallow the rest of the input.
+	// Readers restart on the rest of a single joined input.
+	input := strings.Join(lines, "\n")
+	lineStarts := make([]int, len(lines))
+	for i := 1; i < len(lines); i++ {
+		lineStarts[i] = lineStarts[i-1] + len(lines[i-1]) + 1
+	}
+
+	start := 0
+	for start < len(lines) {
+		text := input[lineStarts[start]:]
+		reader := p.newCSVReader(text, false)
+		offset := int64(0)
+		restart := false
+
+		for !restart {
+			columns, err := reader.Read()
+			if err == io.EOF {
+				return records, nil
+			}
+
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line := start + parseErr.StartLine
+				raw := lines[line-1]
+				// Quotes inside unquoted cells of a single line, like JSON in a
+				// column, are read as they are
+				columns, err := p.newCSVReader(raw, true).Read()
+				if errors.Is(parseErr.Err, csv.ErrBareQuote) && parseErr.StartLine == parseErr.Line && err == nil {
+					if err := row(columns, raw, line); err != nil {
+						return nil, err
+					}
+				} else {
+					records = append(records, models.NewRecord(line, raw))
+				}
+				start = line
+				restart = true
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s input: %w", p.template.Literals.Format, err)
+			}
+
+			line, _ := reader.FieldPos(0)
+			raw := strings.Trim(text[offset:reader.InputOffset()], "\r\n")
+			offset = reader.InputOffset()
+			if err := row(columns, raw, start+line); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return records, nil
+}
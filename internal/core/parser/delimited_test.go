@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+func TestTemplateParser_ParseRecords_CSVHeader(t *testing.T) {
+	p := NewTemplateParser()
+	err := p.SetLiterals(models.TemplateLiterals{
+		Format: models.FormatCSV,
+		Target: "@user@ @action@ [@Audit_Note@]",
+	})
+	if err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	lines := []string{
+		"user,action,Audit Note",
+		`bob,login,"multi`,
+		`line ""quoted"""`,
+		"alice,logout,ok",
+		"too,few",
+	}
+	records, err := p.ParseRecords(lines)
+	if err != nil {
+		t.Fatalf("ParseRecords() error = %v", err)
+	}
+
+	expected := []struct {
+		line    int
+		matched bool
+		output  string
+	}{
+		{line: 2, matched: true, output: "bob login [multi\nline \"quoted\"]"},
+		{line: 4, matched: true, output: "alice logout [ok]"},
+		{line: 5, matched: false, output: "too,few"},
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("ParseRecords() returned %d records, want %d", len(records), len(expected))
+	}
+	for i, want := range expected {
+		record := records[i]
+		if record.Line != want.line || record.Matched != want.matched || record.Output != want.output {
+			t.Errorf("ParseRecords()[%d] = line %d matched %v %q, want line %d matched %v %q",
+				i, record.Line, record.Matched, record.Output, want.line, want.matched, want.output)
+		}
+	}
+}
+
+func TestTemplateParser_ParseRecords_MalformedRow(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetLiterals(models.TemplateLiterals{Format: models.FormatCSV, Target: "@a@-@b@"}); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	// The unterminated quote only makes its own line unmatched
+	records, err := p.ParseRecords([]string{"a,b", "1,2", `"3,4`, "5,6", `7,8 "in"`})
+	if err != nil {
+		t.Fatalf("ParseRecords() error = %v", err)
+	}
+
+	expected := []struct {
+		line    int
+		matched bool
+		output  string
+	}{
+		{line: 2, matched: true, output: "1-2"},
+		{line: 3, matched: false, output: `"3,4`},
+		{line: 4, matched: true, output: "5-6"},
+		{line: 5, matched: true, output: `7-8 "in"`},
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("ParseRecords() returned %d records, want %d", len(records), len(expected))
+	}
+	for i, want := range expected {
+		record := records[i]
+		if record.Line != want.line || record.Matched != want.matched || record.Output != want.output {
+			t.Errorf("ParseRecords()[%d] = line %d matched %v %q, want line %d matched %v %q",
+				i, record.Line, record.Matched, record.Output, want.line, want.matched, want.output)
+		}
+	}
+}
+
+func TestTemplateParser_ParseRecords_BareQuotes(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetLiterals(models.TemplateLiterals{Format: models.FormatTSV, Columns: []string{"id", "body-json"}, Target: "@id@ @body.n@"}); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	// Every row holds quotes inside an unquoted cell, each one read on its own
+	lines := []string{"id\tbody"}
+	for i := 0; i < 20000; i++ {
+		lines = append(lines, fmt.Sprintf("%d\t{\"n\":%d}", i, i))
+	}
+
+	start := time.Now()
+	records, err := p.ParseRecords(lines)
+	if err != nil {
+		t.Fatalf("ParseRecords() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ParseRecords() took %v, want linear time", elapsed)
+	}
+	if len(records) != 20000 || records[19999].Line != 20001 || records[19999].Output != "19999 19999" {
+		t.Fatalf("ParseRecords() returned %d records, last %+v", len(records), records[len(records)-1])
+	}
+}
+
+func TestTemplateParser_ParseRecords_DeclaredColumns(t *testing.T) {
+	header := false
+	tests := []struct {
+		name     string
+		literals models.TemplateLiterals
+		lines    []string
+		want     string
+	}{
+		{
+			name: "Skip header",
+			literals: models.TemplateLiterals{
+				Format:  models.FormatTSV,
+				Columns: []string{"status-number", "body-json"},
+				Target:  "@status@ @body.id@",
+			},
+			lines: []string{"code\tpayload", "200\t{\"id\":7}"},
+			want:  "200 7",
+		},
+		{
+			name: "No header",
+			literals: models.TemplateLiterals{
+				Format:    models.FormatDelimited,
+				Delimiter: ";",
+				Columns:   []string{"a", "b"},
+				Header:    &header,
+				Target:    "@b@-@a@",
+			},
+			lines: []string{"1;2"},
+			want:  "2-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTemplateParser()
+			if err := p.SetLiterals(tt.literals); err != nil {
+				t.Fatalf("Failed to set template: %v", err)
+			}
+
+			records, err := p.ParseRecords(tt.lines)
+			if err != nil {
+				t.Fatalf("ParseRecords() error = %v", err)
+			}
+			if len(records) != 1 || records[0].Output != tt.want {
+				t.Errorf("ParseRecords() = %v, want single record %q", records, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateParser_Delimited_Errors(t *testing.T) {
+	header := false
+	tests := []struct {
+		name     string
+		literals models.TemplateLiterals
+		lines    []string
+	}{
+		{
+			name:     "Missing delimiter",
+			literals: models.TemplateLiterals{Format: models.FormatDelimited, Target: "@a@"},
+		},
+		{
+			name:     "Invalid column type",
+			literals: models.TemplateLiterals{Format: models.FormatCSV, Columns: []string{"a-invalid"}, Target: "@a@"},
+		},
+		{
+			name:     "Duplicate column",
+			literals: models.TemplateLiterals{Format: models.FormatCSV, Columns: []string{"a", "a-number"}, Target: "@a@"},
+		},
+		{
+			name:     "No columns without header",
+			literals: models.TemplateLiterals{Format: models.FormatCSV, Header: &header, Target: "@a@"},
+		},
+		{
+			name:     "Target field missing from header",
+			literals: models.TemplateLiterals{Format: models.FormatCSV, Target: "@missing@"},
+			lines:    []string{"a,b", "1,2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTemplateParser()
+			err := p.SetLiterals(tt.literals)
+			if err == nil {
+				_, err = p.ParseRecords(tt.lines)
+			}
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// TemplateError describes an invalid part of a source or target template
+type TemplateError struct {
+	Template string // Template containing the error, "source" or "target"
+	Literal  string // Template literal
+	Start    int    // Byte offset where the offending part starts
+	End      int    // Byte offset where the offending part ends
+	Message  string // Description of the error
+}
+
+// newTemplateError creates a new TemplateError
+func newTemplateError(template, literal string, start, end int, message string) *TemplateError {
+	return &TemplateError{
+		Template: template,
+		Literal:  literal,
+		Start:    start,
+		End:      end,
+		Message:  message,
+	}
+}
+
+// Error returns the description of the error
+func (e *TemplateError) Error() string {
+	return e.Message
+}
+
+// Caret returns the error along with the template line it occurred on and
+// a caret pointing at the offending part
+func (e *TemplateError) Caret() string {
+	// Find the line of the literal containing the error
+	lineStart := strings.LastIndex(e.Literal[:e.Start], "\n") + 1
+	lineEnd := len(e.Literal)
+	if i := strings.Index(e.Literal[e.Start:], "\n"); i >= 0 {
+		lineEnd = e.Start + i
+	}
+	line := e.Literal[lineStart:lineEnd]
+	lineNumber := strings.Count(e.Literal[:lineStart], "\n") + 1
+
+	prefix := fmt.Sprintf("%s template: ", e.Template)
+	if strings.Contains(e.Literal, "\n") {
+		prefix = fmt.Sprintf("%s template (line %d): ", e.Template, lineNumber)
+	}
+
+	// Columns are counted in characters so the caret lines up with the text
+	column := utf8.RuneCountInString(prefix) + utf8.RuneCountInString(e.Literal[lineStart:e.Start])
+	width := max(utf8.RuneCountInString(e.Literal[e.Start:min(e.End, lineEnd)]), 1)
+
+	return fmt.Sprintf("%s%s\n%s^%s\n%s",
+		prefix, line,
+		strings.Repeat(" ", column), strings.Repeat("~", width-1),
+		e.Message)
+}
@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemplateError_Caret(t *testing.T) {
+	tests := []struct {
+		name           string
+		sourceTemplate string
+		targetTemplate string
+		want           string
+	}{
+		{
+			name:           "Invalid field type",
+			sourceTemplate: "@timestamp-timestamp@ @level-strin@",
+			targetTemplate: "@level@",
+			want: "source template: @timestamp-timestamp@ @level-strin@\n" +
+				"                                              ^~~~~\n" +
+				"invalid field type `strin`",
+		},
+		{
+			name:           "Duplicate field name",
+			sourceTemplate: "@level-string@ @level-string@",
+			targetTemplate: "@level@",
+			want: "source template: @level-string@ @level-string@\n" +
+				"                                 ^~~~~\n" +
+				"duplicate field name `level` in source template",
+		},
+		{
+			name:           "Unknown field in multi-line target",
+			sourceTemplate: "@level-string@ @message-string@",
+			targetTemplate: "Level: @level@\nMessage: @mesage@",
+			want: "target template (line 2): Message: @mesage@\n" +
+				"                                    ^~~~~~\n" +
+				"field `mesage` not found in source template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewTemplateParser().SetTemplate(tt.sourceTemplate, tt.targetTemplate)
+
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("SetTemplate() error = %v, want TemplateError", err)
+			}
+			if got := templateErr.Caret(); got != tt.want {
+				t.Errorf("TemplateError.Caret() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
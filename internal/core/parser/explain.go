@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// Explanation describes how a line was matched against a template
+type Explanation struct {
+	Line     string       `json:"line"`
+	Matched  bool         `json:"matched"`
+	Segments []Segment    `json:"segments"` // Segments of the source template, empty for other input formats
+	Fields   []FieldValue `json:"fields"`   // Values of the fields of a matched line, including sub-fields
+	Stopped  int          `json:"stopped"`  // Index of the segment matching stopped at, -1 if the line matched, len(Segments) if the line goes on after the template
+	Prefix   int          `json:"prefix"`   // Length in bytes of the prefix of the line matched by the segments before Stopped
+	Output   string       `json:"output"`
+}
+
+// Segment is a literal or field part of a source template and the part of a line it matched
+type Segment struct {
+	Field string `json:"field,omitempty"` // Name of the field, empty for literal text
+	Text  string `json:"text"`            // Text of the segment in the source template
+	Width int    `json:"width,omitempty"` // Number of characters of a fixed-width field, 0 if not fixed
+	Start int    `json:"start"`           // Byte offset of the line where the match starts, -1 if not matched
+	End   int    `json:"end"`             // Byte offset of the line where the match ends, -1 if not matched
+}
+
+// FieldValue is the value of a field of a parsed line
+type FieldValue struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`     // Value as captured from the line
+	Formatted string `json:"formatted"` // Value as rendered in the target template
+}
+
+// Explain parses a line and describes which part of the line each segment of
+// the source template matched. If the line doesn't match, it finds the longest
+// prefix of the template that does and the segment where matching stopped.
+func (p *TemplateParser) Explain(line string) *Explanation {
+	record := p.ParseRecord(line)
+	explanation := &Explanation{
+		Line:     line,
+		Matched:  record.Matched,
+		Segments: []Segment{},
+		Fields:   []FieldValue{},
+		Stopped:  -1,
+		Prefix:   len(line),
+		Output:   record.Output,
+	}
+
+	if record.Matched {
+		names := record.Names()
+		sort.Strings(names)
+		for _, name := range names {
+			field, _ := record.Field(name)
+			value, _ := record.Value(name)
+			formatted := p.formatted(record, name)
+			explanation.Fields = append(explanation.Fields, FieldValue{Name: name, Type: field.TypeName(), Value: value, Formatted: formatted})
+		}
+	}
+
+	if models.IsSourceless(p.template.Literals.Format) || p.template.SourceRegex == nil {
+		if !record.Matched {
+			explanation.Prefix = 0
+		}
+		return explanation
+	}
+
+	segments := sourceSegments(p.template.Literals.Source)
+	for _, segment := range segments {
+		explanation.Segments = append(explanation.Segments, Segment{Field: segment.field, Text: segment.text, Width: segment.width, Start: -1, End: -1})
+	}
+
+	if record.Matched {
+		locate(explanation.Segments, segments, p.template.SourceRegex.FindStringSubmatchIndex(line))
+		return explanation
+	}
+
+	// With strict types the whole template may match a line holding a value its
+	// field type rejects, matching stops at that field
+	if match := p.template.SourceRegex.FindStringSubmatchIndex(line); match != nil {
+		locate(explanation.Segments, segments, match)
+		explanation.Stopped, explanation.Prefix = p.rejected(line, explanation.Segments)
+		return explanation
+	}
+
+	// Grow the template one segment at a time until it no longer matches. If all
+	// of it matches, the line goes on after the end of the template.
+	explanation.Prefix = 0
+	explanation.Stopped = len(segments)
+	pattern := "^"
+	for count, segment := range segments {
+		pattern += segment.pattern
+		match := regexp.MustCompile(pattern).FindStringSubmatchIndex(line)
+		if match == nil {
+			explanation.Stopped = count
+			break
+		}
+		locate(explanation.Segments[:count+1], segments[:count+1], match)
+		explanation.Prefix = match[1]
+	}
+	return explanation
+}
+
+// locate sets the byte ranges of a line matched by segments from the submatch
+// indexes of the pattern built from them
+func locate(located []Segment, segments []sourceSegment, match []int) {
+	if match == nil {
+		return
+	}
+
+	// Literals span from the end of the previous field to the start of the next one
+	group := 1
+	position := match[0]
+	for i, segment := range segments {
+		if segment.field != "" {
+			located[i].Start, located[i].End = match[2*group], match[2*group+1]
+			group++
+		} else {
+			located[i].Start, located[i].End = position, match[1]
+			if i+1 < len(segments) {
+				located[i].End = match[2*group]
+			}
+		}
+		position = located[i].End
+	}
+}
+
+// rejected returns the index of the first segment holding a value its field
+// type rejects and the offset of the line where the value starts
+func (p *TemplateParser) rejected(line string, segments []Segment) (int, int) {
+	fields := make(map[string]*models.Field, len(p.template.Fields))
+	for _, field := range p.template.Fields {
+		fields[field.Name] = field
+	}
+
+	for i, segment := range segments {
+		field, ok := fields[segment.Field]
+		if !ok || segment.Start < 0 {
+			continue
+		}
+		value := line[segment.Start:segment.End]
+		if field.Width > 0 {
+			value = trimValue(value, p.template.Literals.Trim)
+		}
+		if _, err := field.Coerce(value); err != nil {
+			return i, segment.Start
+		}
+	}
+	return len(segments), len(line)
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+func TestTemplateParser_Explain(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetTemplate("@level-string@ [@code-number{3}@] @message-string@ end", "@message@"); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		line        string
+		wantMatched bool
+		wantRanges  [][2]int // Byte range matched by each segment
+		wantStopped int
+		wantPrefix  int
+	}{
+		{
+			name:        "Matched",
+			line:        "INFO [200] all good end",
+			wantMatched: true,
+			wantRanges:  [][2]int{{0, 4}, {4, 6}, {6, 9}, {9, 11}, {11, 19}, {19, 23}},
+			wantStopped: -1,
+			wantPrefix:  23,
+		},
+		{
+			name:        "Width takes the bracket",
+			line:        "INFO [20] all good end",
+			wantRanges:  [][2]int{{0, 4}, {4, 6}, {6, 9}, {-1, -1}, {-1, -1}, {-1, -1}},
+			wantStopped: 3,
+			wantPrefix:  9,
+		},
+		{
+			name:        "Missing literal",
+			line:        "INFO [200] all good",
+			wantRanges:  [][2]int{{0, 4}, {4, 6}, {6, 9}, {9, 11}, {11, 11}, {-1, -1}},
+			wantStopped: 5,
+			wantPrefix:  11,
+		},
+		{
+			name:        "Trailing text",
+			line:        "INFO [200] all good end and more",
+			wantRanges:  [][2]int{{0, 4}, {4, 6}, {6, 9}, {9, 11}, {11, 19}, {19, 23}},
+			wantStopped: 6,
+			wantPrefix:  23,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation := p.Explain(tt.line)
+			if explanation.Matched != tt.wantMatched {
+				t.Errorf("Explain() matched = %v, want %v", explanation.Matched, tt.wantMatched)
+			}
+
+			ranges := [][2]int{}
+			for _, segment := range explanation.Segments {
+				ranges = append(ranges, [2]int{segment.Start, segment.End})
+			}
+			if !reflect.DeepEqual(ranges, tt.wantRanges) {
+				t.Errorf("Explain() ranges = %v, want %v", ranges, tt.wantRanges)
+			}
+			if explanation.Stopped != tt.wantStopped || explanation.Prefix != tt.wantPrefix {
+				t.Errorf("Explain() stopped, prefix = %d, %d, want %d, %d",
+					explanation.Stopped, explanation.Prefix, tt.wantStopped, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestTemplateParser_Explain_Fields(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetLiterals(models.TemplateLiterals{Format: models.FormatLogfmt, Target: "@msg@"}); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	explanation := p.Explain(`status=200 msg="done"`)
+	want := []FieldValue{
+		{Name: "msg", Type: "string", Value: "done", Formatted: "done"},
+		{Name: "status", Type: "number", Value: "200", Formatted: "200"},
+	}
+	if !explanation.Matched || len(explanation.Segments) != 0 || !reflect.DeepEqual(explanation.Fields, want) {
+		t.Errorf("Explain() = %+v, want fields %v", explanation, want)
+	}
+}
+
+func TestTemplateParser_Explain_StrictTypes(t *testing.T) {
+	p := NewTemplateParser()
+	literals := models.TemplateLiterals{Source: "@client-ip@ took @took-duration@", Target: "@client@", StrictTypes: true}
+	if err := p.SetLiterals(literals); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	// Matching stops at the field whose type rejects the value
+	explanation := p.Explain("10.0.0.1 took soon")
+	if explanation.Matched || explanation.Stopped != 2 || explanation.Prefix != 14 {
+		t.Errorf("Explain() = matched %v, stopped %d, prefix %d, want unmatched, stopped 2, prefix 14",
+			explanation.Matched, explanation.Stopped, explanation.Prefix)
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/stacktrace"
+)
+
+// jsonKeyRegex matches characters of JSON keys that cannot be used in field names
+var jsonKeyRegex = regexp.MustCompile(`[^\w]`)
+
+// parseJSONRecord parses a log entry holding a JSON object. Keys of nested
+// objects are addressable as dotted field names like "http.status", and the
+// type of each field is inferred from its JSON value. Entries that are not
+// JSON objects are returned as unmatched records.
+func (p *TemplateParser) parseJSONRecord(sourceLog string) *models.Record {
+	record := models.NewRecord(0, sourceLog)
+
+	trimmed := strings.TrimSpace(sourceLog)
+	if !strings.HasPrefix(trimmed, "{") || !json.Valid([]byte(trimmed)) {
+		return record
+	}
+
+	if err := p.flattenJSON(json.RawMessage(trimmed), "", record); err != nil {
+		return models.NewRecord(0, sourceLog)
+	}
+	record.Matched = true
+	return record
+}
+
+// flattenJSON adds the keys of a JSON object to a record in document order
+func (p *TemplateParser) flattenJSON(object json.RawMessage, prefix string, record *models.Record) error {
+	decoder := json.NewDecoder(bytes.NewReader(object))
+
+	// Consume opening brace
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		name := prefix + jsonKeyRegex.ReplaceAllString(token.(string), "_")
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		switch value[0] {
+		case '{':
+			record.Set(&models.Field{Name: name, Type: models.JSON}, string(value))
+			if err := p.flattenJSON(value, name+".", record); err != nil {
+				return err
+			}
+		case '[':
+			record.Set(&models.Field{Name: name, Type: models.JSON}, string(value))
+		case '"':
+			var text string
+			if err := json.Unmarshal(value, &text); err != nil {
+				return err
+			}
+			field := &models.Field{Name: name, Type: models.String}
+			if strings.Contains(text, "\n") {
+				// Stack traces logged as a string, like the stack of an error.
+				// Traces span several lines, so single lines aren't parsed.
+				if _, ok := stacktrace.Parse(text); ok {
+					field.Type = models.Stacktrace
+				}
+			} else if _, err := models.ParseTimestamp(text); err == nil {
+				field.Type = models.Timestamp
+			}
+			record.Set(field, text)
+			p.addSubFields(field, text, record)
+		case 't', 'f':
+			record.Set(&models.Field{Name: name, Type: models.Bool}, string(value))
+		case 'n':
+			record.Set(&models.Field{Name: name, Type: models.Raw}, "")
+		default:
+			record.Set(&models.Field{Name: name, Type: models.Number}, string(value))
+		}
+	}
+	return nil
+}
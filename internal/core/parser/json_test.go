@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+func TestTemplateParser_ParseRecord_JSON(t *testing.T) {
+	p := NewTemplateParser()
+	err := p.SetLiterals(models.TemplateLiterals{
+		Format: models.FormatJSON,
+		Target: "[@level@] @msg@ status=@http.status@ agent=@user_agent@ missing=@missing@",
+	})
+	if err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		log     string
+		matched bool
+		want    string
+	}{
+		{
+			name:    "Nested object",
+			log:     `{"level":"info","msg":"done","http":{"status":200},"user-agent":"curl"}`,
+			matched: true,
+			want:    "[info] done status=200 agent=curl missing=",
+		},
+		{
+			name:    "Surrounding whitespace",
+			log:     `  {"level":"warn","msg":"slow"}  `,
+			matched: true,
+			want:    "[warn] slow status= agent= missing=",
+		},
+		{
+			name: "Plain text line",
+			log:  "panic: runtime error",
+			want: "panic: runtime error",
+		},
+		{
+			name: "Invalid JSON",
+			log:  `{"level":"info"`,
+			want: `{"level":"info"`,
+		},
+		{
+			name: "JSON array",
+			log:  `["a","b"]`,
+			want: `["a","b"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := p.ParseRecord(tt.log)
+			if record.Matched != tt.matched {
+				t.Errorf("ParseRecord() matched = %v, want %v", record.Matched, tt.matched)
+			}
+			if record.Output != tt.want {
+				t.Errorf("ParseRecord() output = %q, want %q", record.Output, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONRecord_Types(t *testing.T) {
+	record := NewTemplateParser().parseJSONRecord(`{"n":1.5,"s":"x","b":true,"f":false,"ts":"2024-01-02T03:04:05Z","z":null,"a":[1,2],"o":{"k":"v"},"st":"Error: x\n    at f (/app/a.js:1:2)","frame":"at f (/app/a.js:1:2)"}`)
+	if !record.Matched {
+		t.Fatalf("parseJSONRecord() did not match")
+	}
+
+	tests := []struct {
+		name      string
+		fieldType models.FieldType
+		value     string
+	}{
+		{name: "n", fieldType: models.Number, value: "1.5"},
+		{name: "s", fieldType: models.String, value: "x"},
+		{name: "b", fieldType: models.Bool, value: "true"},
+		{name: "f", fieldType: models.Bool, value: "false"},
+		{name: "ts", fieldType: models.Timestamp, value: "2024-01-02T03:04:05Z"},
+		{name: "z", fieldType: models.Raw, value: ""},
+		{name: "a", fieldType: models.JSON, value: "[1,2]"},
+		{name: "o", fieldType: models.JSON, value: `{"k":"v"}`},
+		{name: "o.k", fieldType: models.String, value: "v"},
+		{name: "st", fieldType: models.Stacktrace, value: "Error: x\n    at f (/app/a.js:1:2)"},
+		{name: "st.top", fieldType: models.String, value: "f (/app/a.js:1:2)"},
+		// Traces span several lines, a single frame is a string
+		{name: "frame", fieldType: models.String, value: "at f (/app/a.js:1:2)"},
+	}
+
+	for _, tt := range tests {
+		field, ok := record.Field(tt.name)
+		if !ok {
+			t.Errorf("field %s not found", tt.name)
+			continue
+		}
+		value, _ := record.Value(tt.name)
+		if field.Type != tt.fieldType || value != tt.value {
+			t.Errorf("field %s = %v %q, want %v %q", tt.name, field.Type, value, tt.fieldType, tt.value)
+		}
+	}
+
+	// Keys are kept in document order
+	want := []string{"n", "s", "b", "f", "ts", "z", "a", "o", "o.k", "st", "st.top", "frame"}
+	for i, name := range record.Names() {
+		if name != want[i] {
+			t.Errorf("Names()[%d] = %v, want %v", i, name, want[i])
+		}
+	}
+
+	// Timestamp strings give the record its time
+	timestamp, ok := record.Time("")
+	if !ok || !timestamp.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("Time() = %v, %v, want 2024-01-02T03:04:05Z", timestamp, ok)
+	}
+}
+
+func TestTemplateParser_SetLiterals_JSONErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		literals models.TemplateLiterals
+	}{
+		{name: "Missing target", literals: models.TemplateLiterals{Format: models.FormatJSON}},
+		{name: "Source template", literals: models.TemplateLiterals{Format: models.FormatJSON, Source: "@a-string@", Target: "@a@"}},
+		{name: "Unknown format", literals: models.TemplateLiterals{Format: "xml", Target: "@a@"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewTemplateParser().SetLiterals(tt.literals); err == nil {
+				t.Errorf("SetLiterals() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,449 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gitKashish/golog/internal/core/color"
+	"github.com/gitKashish/golog/internal/core/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of lint issues
+const (
+	SeverityError   = "error"   // The template can't be used
+	SeverityWarning = "warning" // The template likely behaves differently than intended
+)
+
+// looseFieldRegex finds text that looks like a field but isn't one
+var looseFieldRegex = regexp.MustCompile(`@([\w.-]+(?:\{\w*\})?)@`)
+
+// Issue is a problem found in a template
+type Issue struct {
+	Severity string
+	Template string // Template containing the issue, "source" or "target", empty if it concerns the whole template
+	Start    int    // Byte offset where the offending part starts
+	End      int    // Byte offset where the offending part ends
+	Message  string // Description of the issue
+}
+
+// Diagnostic is an issue located in a template file
+type Diagnostic struct {
+	Issue
+	Line   int // Line of the template file, 0 if the issue isn't located in the file
+	Column int // Column of the template file in characters
+}
+
+// Lint checks template literals for errors and for parts that likely behave
+// differently than intended: fields without a delimiter between them, fields
+// not used in the target template, misspelled fields, `@` characters that are
+// not escaped and whitespace that is matched flexibly.
+func Lint(literals models.TemplateLiterals) []Issue {
+	issues := []Issue{}
+	if literals.Format == "" || literals.Format == models.FormatTemplate {
+		issues = append(issues, lintSource(literals.Source)...)
+		issues = append(issues, lintTarget(literals.Source, literals.Target)...)
+	} else {
+		issues = append(issues, lintEscapes("target", literals.Target, nil)...)
+	}
+
+	// Errors of the parser not reported yet, like duplicate field names
+	p := NewTemplateParser()
+	if err := p.SetLiterals(literals); err != nil {
+		issue := Issue{Severity: SeverityError, Message: err.Error()}
+		var templateErr *TemplateError
+		if errors.As(err, &templateErr) {
+			issue.Template, issue.Start, issue.End = templateErr.Template, templateErr.Start, templateErr.End
+		}
+		if !hasError(issues, issue) {
+			issues = append(issues, issue)
+		}
+	}
+
+	// Report issues in the order they appear in the templates
+	order := map[string]int{"": 0, "source": 1, "target": 2}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Template != issues[j].Template {
+			return order[issues[i].Template] < order[issues[j].Template]
+		}
+		return issues[i].Start < issues[j].Start
+	})
+	return issues
+}
+
+// LintFile checks the template of a file, locating issues in the file
+func LintFile(templatePath string) ([]Diagnostic, error) {
+	literals, err := LoadLiterals(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template file: %w", err)
+	}
+	root := yaml.Node{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("error parsing template file: %w", err)
+	}
+
+	// Templates inherited from a preset have no node and aren't located
+	nodes := map[string]*yaml.Node{
+		"source": mappingValue(&root, "sourceTemplate"),
+		"target": mappingValue(&root, "targetTemplate"),
+	}
+	lines := strings.Split(string(data), "\n")
+
+	diagnostics := []Diagnostic{}
+	for _, issue := range Lint(literals) {
+		diagnostic := Diagnostic{Issue: issue}
+		if node := nodes[issue.Template]; node != nil {
+			diagnostic.Line, diagnostic.Column = position(node, lines, issue.Start)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics, nil
+}
+
+// lintSource checks the fields and literal text of a source template
+func lintSource(source string) []Issue {
+	issues := []Issue{}
+	matches := sourceFieldRegex.FindAllStringSubmatchIndex(maskEscapes(source), -1)
+
+	for i, match := range matches {
+		typeName := source[match[4]:match[5]]
+		if _, _, err := models.ParseTypeName(typeName); err != nil {
+			issues = append(issues, Issue{
+				Severity: SeverityError, Template: "source", Start: match[4], End: match[5],
+				Message: err.Error() + didYouMean(unknownType(typeName), models.FieldTypeNames()),
+			})
+		}
+
+		// Lazy fields followed directly by another field capture nothing
+		if i > 0 && matches[i-1][1] == match[0] && !hasWidth(matches[i-1]) {
+			previous := source[matches[i-1][2]:matches[i-1][3]]
+			issues = append(issues, Issue{
+				Severity: SeverityWarning, Template: "source", Start: matches[i-1][0], End: match[1],
+				Message: fmt.Sprintf("fields `%s` and `%s` have no delimiter between them, so where one ends is ambiguous; add a delimiter or a width to `%s`",
+					previous, source[match[2]:match[3]], previous),
+			})
+		}
+	}
+
+	issues = append(issues, lintEscapes("source", source, matches)...)
+	issues = append(issues, lintWhitespace(source, matches)...)
+	return issues
+}
+
+// lintTarget checks the fields of a target template against the source template
+func lintTarget(source, target string) []Issue {
+	issues := []Issue{}
+	sourceMatches := sourceFieldRegex.FindAllStringSubmatchIndex(maskEscapes(source), -1)
+	names := make([]string, len(sourceMatches))
+	types := map[string]string{}
+	for i, match := range sourceMatches {
+		names[i] = source[match[2]:match[3]]
+		types[names[i]] = source[match[4]:match[5]]
+	}
+
+	matches := targetFieldRegex.FindAllStringSubmatchIndex(maskEscapes(target), -1)
+	used := map[string]bool{}
+	for _, match := range matches {
+		name := target[match[2]:match[3]]
+		root, _, isSubField := strings.Cut(name, ".")
+		used[root] = true
+
+		if match[4] >= 0 && !color.IsStyle(target[match[4]:match[5]]) {
+			issues = append(issues, Issue{
+				Severity: SeverityError, Template: "target", Start: match[4], End: match[5],
+				Message: fmt.Sprintf("unknown style `%s` of field `%s`", target[match[4]:match[5]], name),
+			})
+		}
+
+		fieldType, declared := types[root]
+		switch {
+		case !declared:
+			candidates := names
+			if isSubField {
+				candidates = subFieldCandidates(name, names, types)
+			}
+			issues = append(issues, Issue{
+				Severity: SeverityError, Template: "target", Start: match[2], End: match[3],
+				Message: fmt.Sprintf("field `%s` not found in source template", name) + didYouMean(name, candidates),
+			})
+		case isSubField && !hasSubFieldType(fieldType):
+			issues = append(issues, Issue{
+				Severity: SeverityError, Template: "target", Start: match[2], End: match[3],
+				Message: fmt.Sprintf("field `%s` not found in source template, `%s` has no sub-fields", name, root),
+			})
+		}
+	}
+
+	for i, match := range sourceMatches {
+		if !used[names[i]] {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning, Template: "source", Start: match[2], End: match[3],
+				Message: fmt.Sprintf("field `%s` is not used in the target template", names[i]),
+			})
+		}
+	}
+
+	return append(issues, lintEscapes("target", target, matches)...)
+}
+
+// lintEscapes reports text of a template that looks like a field but isn't one,
+// and `@` characters that are not part of a field and not escaped
+func lintEscapes(template, literal string, matches [][]int) []Issue {
+	// Hide the fields so that only the remaining text is checked
+	masked := []byte(maskEscapes(literal))
+	for _, match := range matches {
+		for i := match[0]; i < match[1]; i++ {
+			masked[i] = 0
+		}
+	}
+
+	issues := []Issue{}
+	for _, loose := range looseFieldRegex.FindAllSubmatchIndex(masked, -1) {
+		text := literal[loose[0]:loose[1]]
+		message := fmt.Sprintf("`%s` looks like a field but is matched literally, source fields are written as `@name-type@`", text)
+		if template == "target" {
+			message = fmt.Sprintf("`%s` looks like a field but is written as is, target fields are written as `@name@`", text)
+		}
+		issues = append(issues, Issue{Severity: SeverityWarning, Template: template, Start: loose[0], End: loose[1], Message: message})
+		for i := loose[0]; i < loose[1]; i++ {
+			masked[i] = 0
+		}
+	}
+
+	for i, c := range masked {
+		if c == '@' {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning, Template: template, Start: i, End: i + 1,
+				Message: "unescaped `@` outside of a field, write `\\@` for a literal `@`",
+			})
+		}
+	}
+	return issues
+}
+
+// lintWhitespace reports whitespace of a source template that doesn't match the
+// way it reads: runs of whitespace match any amount of whitespace, and leading or
+// trailing whitespace must be present in every line
+func lintWhitespace(source string, matches [][]int) []Issue {
+	issues := []Issue{}
+	spaceRegex := regexp.MustCompile(`\s+`)
+
+	last := 0
+	for i := 0; i <= len(matches); i++ {
+		end := len(source)
+		if i < len(matches) {
+			end = matches[i][0]
+		}
+
+		// Literals next to width-specified fields are matched exactly
+		exact := (i < len(matches) && hasWidth(matches[i])) || (i > 0 && hasWidth(matches[i-1]))
+		if !exact {
+			for _, space := range spaceRegex.FindAllStringIndex(source[last:end], -1) {
+				start, stop := last+space[0], last+space[1]
+				if message := whitespaceMessage(source, start, stop); message != "" {
+					issues = append(issues, Issue{Severity: SeverityWarning, Template: "source", Start: start, End: stop, Message: message})
+				}
+			}
+		}
+
+		if i < len(matches) {
+			last = matches[i][1]
+		}
+	}
+	return issues
+}
+
+// whitespaceMessage describes the surprising part of a run of whitespace in a
+// source template, if any
+func whitespaceMessage(source string, start, end int) string {
+	space := source[start:end]
+	switch {
+	case end == len(source) && strings.Contains(space, "\n"):
+		return "source template ends with a line break, which lines read from a file never do; use `|-` instead of `|` in template.yaml"
+	case end == len(source):
+		return "source template ends with whitespace, so only lines ending with whitespace match"
+	case start == 0:
+		return "source template starts with whitespace, so only lines starting with whitespace match"
+	case strings.Contains(space, "\n"):
+		return "line break in source template matches any whitespace, lines are parsed one at a time"
+	case space != " ":
+		return fmt.Sprintf("whitespace %q matches any run of whitespace, including a single space", space)
+	}
+	return ""
+}
+
+// didYouMean suggests the candidate closest to a misspelled name, if any is close
+func didYouMean(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/3+1
+	for _, candidate := range candidates {
+		if distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate)); distance <= bestDistance && distance < len(candidate) {
+			if best == "" || distance < bestDistance {
+				best, bestDistance = candidate, distance
+			}
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean `%s`?", best)
+}
+
+// subFieldCandidates returns a sub-field name under each structured field, as
+// candidates for a sub-field whose field is misspelled
+func subFieldCandidates(name string, names []string, types map[string]string) []string {
+	_, rest, _ := strings.Cut(name, ".")
+	candidates := []string{}
+	for _, field := range names {
+		if hasSubFieldType(types[field]) {
+			candidates = append(candidates, field+"."+rest)
+		}
+	}
+	return candidates
+}
+
+// unknownType returns the part of a type name like "base64+jsn" that is not a
+// field type, empty if both parts are
+func unknownType(typeName string) string {
+	for _, part := range strings.Split(typeName, "+") {
+		if _, err := models.FieldTypeFromString(part); err != nil {
+			return part
+		}
+	}
+	return ""
+}
+
+// hasSubFieldType checks whether fields of a named type have sub-fields
+func hasSubFieldType(typeName string) bool {
+	fieldType, decoded, err := models.ParseTypeName(typeName)
+	return err == nil && (&models.Field{Type: fieldType, Decoded: decoded}).HasSubFields()
+}
+
+// hasError checks whether an error issue was already reported at the same place
+func hasError(issues []Issue, issue Issue) bool {
+	for _, other := range issues {
+		if other.Severity == SeverityError && other.Template == issue.Template && other.Start == issue.Start {
+			return true
+		}
+	}
+	return false
+}
+
+// levenshtein returns the number of single character edits between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			diagonal, row[j] = row[j], min(row[j]+1, row[j-1]+1, diagonal+cost)
+		}
+	}
+	return row[len(rb)]
+}
+
+// mappingValue returns the value node of a key of a YAML document, nil if missing
+func mappingValue(root *yaml.Node, key string) *yaml.Node {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key && node.Content[i+1].Kind == yaml.ScalarNode {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// position returns the line and column of a file holding a byte offset into the
+// value of a scalar node. Offsets into folded and multi-line flow scalars, which
+// don't map to the file character by character, are located at the node.
+func position(node *yaml.Node, lines []string, offset int) (int, int) {
+	value := node.Value
+	offset = min(offset, len(value))
+
+	switch node.Style {
+	case yaml.LiteralStyle:
+		// Content starts on the line after the indicator, indented like its first line
+		indent := 0
+		for _, line := range lines[min(node.Line, len(lines)):] {
+			if strings.TrimSpace(line) != "" {
+				indent = len(line) - len(strings.TrimLeft(line, " "))
+				break
+			}
+		}
+		lineStart := strings.LastIndex(value[:offset], "\n") + 1
+		return node.Line + 1 + strings.Count(value[:offset], "\n"), indent + utf8.RuneCountInString(value[lineStart:offset]) + 1
+	case yaml.FoldedStyle:
+		return node.Line, node.Column
+	}
+
+	if strings.Contains(value, "\n") || node.Line > len(lines) {
+		return node.Line, node.Column
+	}
+
+	// Walk the characters of the line, decoding quotes and escapes
+	runes := []rune(lines[node.Line-1])
+	i := node.Column - 1
+	if node.Style == yaml.SingleQuotedStyle || node.Style == yaml.DoubleQuotedStyle {
+		i++
+	}
+	for decoded := 0; decoded < offset && i < len(runes); {
+		size, length := 1, utf8.RuneLen(runes[i])
+		switch {
+		case node.Style == yaml.SingleQuotedStyle && runes[i] == '\'':
+			size = 2 // A quote is written as ''
+		case node.Style == yaml.DoubleQuotedStyle && runes[i] == '\\':
+			size, length = escapeLength(runes[i+1:])
+		}
+		decoded += length
+		i += size
+	}
+	if i > len(runes) {
+		return node.Line, node.Column
+	}
+	return node.Line, i + 1
+}
+
+// escapeLength returns the number of characters of a double quoted YAML escape
+// following the backslash, including it, and the number of bytes it decodes to
+func escapeLength(rest []rune) (int, int) {
+	if len(rest) == 0 {
+		return 1, 0
+	}
+	digits := map[rune]int{'x': 2, 'u': 4, 'U': 8}[rest[0]]
+	if digits > 0 && len(rest) > digits {
+		if code, err := strconv.ParseUint(string(rest[1:digits+1]), 16, 32); err == nil {
+			return digits + 2, utf8.RuneLen(rune(code))
+		}
+	}
+	switch rest[0] {
+	case 'N', '_':
+		return 2, 2
+	case 'L', 'P':
+		return 2, 3
+	}
+	return 2, 1
+}
@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		target string
+		want   []string // Severity and offending text of each issue
+	}{
+		{
+			name:   "Clean",
+			source: "@time-timestamp@ [@level-string@] @message-string@",
+			target: "@level@ @message@ (@time@)",
+			want:   []string{},
+		},
+		{
+			name:   "Adjacent fields",
+			source: "@host-string@@code-number@ @message-string@",
+			target: "@host@ @code@ @message@",
+			want:   []string{"warning @host-string@@code-number@"},
+		},
+		{
+			name:   "Adjacent fixed-width field",
+			source: "@host-string{8}@@code-number@ @message-string@",
+			target: "@host@ @code@ @message@",
+			want:   []string{},
+		},
+		{
+			name:   "Unknown style",
+			source: "@level-string@ @message-string@",
+			target: "@level:color@ @message:pink@",
+			want:   []string{"error pink"},
+		},
+		{
+			name:   "Unused field",
+			source: "@time-string@ @message-string@",
+			target: "@message@",
+			want:   []string{"warning time"},
+		},
+		{
+			name:   "Misspelled target field",
+			source: "@level-string@ @message-string@",
+			target: "@levle@ @message@",
+			want:   []string{"warning level", "error levle"},
+		},
+		{
+			name:   "Misspelled type",
+			source: "@level-strin@ @message-string@",
+			target: "@level@ @message@",
+			want:   []string{"error strin"},
+		},
+		{
+			name:   "Field without type",
+			source: "@level@ @message-string@",
+			target: "@message@",
+			want:   []string{"warning @level@"},
+		},
+		{
+			name:   "Source field in target",
+			source: "@message-string@",
+			target: "@message-string@",
+			want:   []string{"warning message", "warning @message-string@"},
+		},
+		{
+			name:   "Unescaped at",
+			source: `@user-string@@@host-string@ \@ ok`,
+			target: "@user@ @ @host@",
+			want:   []string{"warning @", "warning @"},
+		},
+		{
+			name:   "Whitespace",
+			source: " @a-string@  @b-string@\t@c-string@\n",
+			target: "@a@ @b@ @c@",
+			want:   []string{"warning  ", "warning   ", "warning \t", "warning \n"},
+		},
+		{
+			name:   "Duplicate field",
+			source: "@a-string@ @a-string@",
+			target: "@a@",
+			want:   []string{"error a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := Lint(models.TemplateLiterals{Source: tt.source, Target: tt.target})
+
+			got := []string{}
+			for _, issue := range issues {
+				literal := tt.source
+				if issue.Template == "target" {
+					literal = tt.target
+				}
+				got = append(got, issue.Severity+" "+literal[issue.Start:issue.End])
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Lint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLint_DidYouMean(t *testing.T) {
+	issues := Lint(models.TemplateLiterals{Source: "@details-json@ @level-strng@", Target: "@detail.code@ @level@"})
+
+	// The misspelled field leaves details unused as well
+	want := []string{
+		"field `details` is not used in the target template",
+		"invalid field type `strng`, did you mean `string`?",
+		"field `detail.code` not found in source template, did you mean `details.code`?",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Lint() = %v, want %d issues", issues, len(want))
+	}
+	for i, issue := range issues {
+		if issue.Message != want[i] {
+			t.Errorf("Lint() message = %q, want %q", issue.Message, want[i])
+		}
+	}
+}
+
+func TestLint_ChainedTypes(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "@body-base64+json@", want: ""},
+		{source: "@body-base64+jsno@", want: "invalid field type `jsno`, did you mean `json`?"},
+		{source: "@body-base46+json@", want: "invalid field type `base46`, did you mean `base64`?"},
+		{source: "@body-json+base64@", want: "field type `json` has no decoded value to chain `base64` to"},
+		{source: "@body-quoted+base64@", want: "decoded values can't be decoded again as `base64`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got := ""
+			for _, issue := range Lint(models.TemplateLiterals{Source: tt.source, Target: "@body@"}) {
+				if issue.Severity == SeverityError {
+					got = issue.Message
+				}
+			}
+			if got != tt.want {
+				t.Errorf("Lint() error = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:       "Single quoted",
+			content:    "sourceTemplate: '@a-string@ @b-string@'\ntargetTemplate: '@a@ @b@ @bb@'\n",
+			wantLine:   2,
+			wantColumn: 27,
+		},
+		{
+			name:       "Double quoted with escapes",
+			content:    "sourceTemplate: '@a-string@ @b-string@'\ntargetTemplate: \"\\u00e9\\t@a@ @b@ @bb@\"\n",
+			wantLine:   2,
+			wantColumn: 35,
+		},
+		{
+			name:       "Literal block",
+			content:    "sourceTemplate: \"@a-string@ @b-string@\"\ntargetTemplate: |\n    A: @a@ @b@\n    B: @bb@\n",
+			wantLine:   4,
+			wantColumn: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "template.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write template: %v", err)
+			}
+
+			diagnostics, err := LintFile(path)
+			if err != nil {
+				t.Fatalf("LintFile() error = %v", err)
+			}
+			if len(diagnostics) != 1 {
+				t.Fatalf("LintFile() = %v, want 1 diagnostic", diagnostics)
+			}
+			if d := diagnostics[0]; d.Line != tt.wantLine || d.Column != tt.wantColumn {
+				t.Errorf("LintFile() position = %d:%d, want %d:%d", d.Line, d.Column, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// kvKeyRegex matches characters of keys that cannot be used in field names
+var kvKeyRegex = regexp.MustCompile(`[^\w.]`)
+
+// KeyValue is a single pair of a key=value sequence
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// ParseKeyValues splits a logfmt style sequence like `level=info msg="a \"b\"" ok`
+// into its pairs. Quoted values may contain spaces and escaped quotes, and keys
+// without a value get an empty value. It returns false if a quoted value is not
+// terminated or the sequence holds no key=value pair at all.
+func ParseKeyValues(text string) ([]KeyValue, bool) {
+	pairs := []KeyValue{}
+	hasValue := false
+
+	i := 0
+	for i < len(text) {
+		// Skip whitespace between pairs
+		if text[i] == ' ' || text[i] == '\t' {
+			i++
+			continue
+		}
+
+		// Read key up to the separator
+		start := i
+		for i < len(text) && text[i] != '=' && text[i] != ' ' && text[i] != '\t' && text[i] != '"' {
+			i++
+		}
+		key := text[start:i]
+		if key == "" {
+			return nil, false
+		}
+
+		if i >= len(text) || text[i] != '=' {
+			pairs = append(pairs, KeyValue{Key: key})
+			continue
+		}
+		i++ // Skip '='
+		hasValue = true
+
+		// Read quoted value honoring escapes
+		if i < len(text) && text[i] == '"' {
+			end := i + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(text) {
+				return nil, false
+			}
+			pairs = append(pairs, KeyValue{Key: key, Value: unquote(text[i : end+1])})
+			i = end + 1
+			continue
+		}
+
+		// Read bare value up to the next whitespace
+		start = i
+		for i < len(text) && text[i] != ' ' && text[i] != '\t' {
+			i++
+		}
+		pairs = append(pairs, KeyValue{Key: key, Value: text[start:i]})
+	}
+
+	return pairs, hasValue
+}
+
+// unquote removes quotes and escapes from a quoted value
+func unquote(quoted string) string {
+	if value, err := strconv.Unquote(quoted); err == nil {
+		return value
+	}
+	// Fall back to unescaping quotes and backslashes only
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(quoted[1 : len(quoted)-1])
+}
+
+// parseLogfmtRecord parses a log entry made of key=value pairs. Every key
+// becomes a field, later occurrences of a repeated key override earlier ones.
+func parseLogfmtRecord(sourceLog string) *models.Record {
+	record := models.NewRecord(0, sourceLog)
+
+	pairs, ok := ParseKeyValues(strings.TrimSpace(sourceLog))
+	if !ok {
+		return record
+	}
+
+	addKeyValues(pairs, "", record)
+	record.Matched = true
+	return record
+}
+
+// addKeyValues adds key=value pairs to a record as fields with inferred types
+func addKeyValues(pairs []KeyValue, prefix string, record *models.Record) {
+	for _, pair := range pairs {
+		name := prefix + kvKeyRegex.ReplaceAllString(pair.Key, "_")
+		fieldType := models.String
+		if _, err := strconv.ParseFloat(pair.Value, 64); err == nil {
+			fieldType = models.Number
+		} else if pair.Value == "true" || pair.Value == "false" {
+			fieldType = models.Bool
+		} else if _, err := models.ParseTimestamp(pair.Value); err == nil {
+			fieldType = models.Timestamp
+		}
+		record.Set(&models.Field{Name: name, Type: fieldType}, pair.Value)
+	}
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []KeyValue
+		ok   bool
+	}{
+		{
+			name: "Bare values",
+			text: "level=info status=200 dur=12ms",
+			want: []KeyValue{{"level", "info"}, {"status", "200"}, {"dur", "12ms"}},
+			ok:   true,
+		},
+		{
+			name: "Quoted value with escaped quotes",
+			text: `msg="user \"bob\" logged in" ok=true`,
+			want: []KeyValue{{"msg", `user "bob" logged in`}, {"ok", "true"}},
+			ok:   true,
+		},
+		{
+			name: "Key without value",
+			text: "debug level=info",
+			want: []KeyValue{{"debug", ""}, {"level", "info"}},
+			ok:   true,
+		},
+		{
+			name: "Empty value",
+			text: `a= b=""`,
+			want: []KeyValue{{"a", ""}, {"b", ""}},
+			ok:   true,
+		},
+		{
+			name: "Unterminated quote",
+			text: `msg="oops`,
+			ok:   false,
+		},
+		{
+			name: "Plain text",
+			text: "just some words",
+			ok:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseKeyValues(tt.text)
+			if ok != tt.ok {
+				t.Fatalf("ParseKeyValues() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseKeyValues() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseKeyValues()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateParser_ParseRecord_Logfmt(t *testing.T) {
+	p := NewTemplateParser()
+	err := p.SetLiterals(models.TemplateLiterals{
+		Format: models.FormatLogfmt,
+		Target: "[@level@] @msg@ (@status@)",
+	})
+	if err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	record := p.ParseRecord(`ts=2024-01-02T10:00:00Z level=info msg="request done" status=200 status=404`)
+	if !record.Matched {
+		t.Fatalf("ParseRecord() did not match")
+	}
+	// Repeated keys keep the last value
+	if want := "[info] request done (404)"; record.Output != want {
+		t.Errorf("ParseRecord() output = %q, want %q", record.Output, want)
+	}
+	if field, _ := record.Field("status"); field.Type != models.Number {
+		t.Errorf("status type = %v, want number", field.Type)
+	}
+
+	if record := p.ParseRecord("plain text line"); record.Matched {
+		t.Errorf("ParseRecord() matched plain text")
+	}
+}
+
+func TestTemplateParser_ParseRecord_LogfmtTypes(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetLiterals(models.TemplateLiterals{Format: models.FormatLogfmt, Target: "@msg@"}); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	record := p.ParseRecord(`ts=2024-01-02T10:00:00Z cached=true retried=false status=200 msg=yes`)
+	if !record.Matched {
+		t.Fatalf("ParseRecord() did not match")
+	}
+
+	tests := []struct {
+		name      string
+		fieldType models.FieldType
+	}{
+		{name: "ts", fieldType: models.Timestamp},
+		{name: "cached", fieldType: models.Bool},
+		{name: "retried", fieldType: models.Bool},
+		{name: "status", fieldType: models.Number},
+		{name: "msg", fieldType: models.String},
+	}
+	for _, tt := range tests {
+		if field, ok := record.Field(tt.name); !ok || field.Type != tt.fieldType {
+			t.Errorf("field %s type = %v, want %v", tt.name, field, tt.fieldType)
+		}
+	}
+
+	// Timestamp values give the record its time
+	timestamp, ok := record.Time("")
+	if !ok || !timestamp.Equal(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time() = %v, %v, want 2024-01-02T10:00:00Z", timestamp, ok)
+	}
+}
+
+func TestTemplateParser_ParseRecord_KVField(t *testing.T) {
+	p := NewTemplateParser()
+	err := p.SetTemplate("@ts-string@ @rest-kv@", "@ts@ status=@rest.status@ msg=@rest.msg@")
+	if err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	got := p.Parse(`2024-01-02T10:00:00Z status=200 msg="all \"good\""`)
+	if want := `2024-01-02T10:00:00Z status=200 msg=all "good"`; got != want {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateParser_SetTemplate_SubFieldErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		sourceTemplate string
+		targetTemplate string
+		wantErr        bool
+	}{
+		{name: "KV sub-field", sourceTemplate: "@rest-kv@", targetTemplate: "@rest.status@", wantErr: false},
+		{name: "JSON sub-field", sourceTemplate: "@details-json@", targetTemplate: "@details.ERROR.code@", wantErr: false},
+		{name: "String sub-field", sourceTemplate: "@msg-string@", targetTemplate: "@msg.status@", wantErr: true},
+		{name: "Unknown root", sourceTemplate: "@rest-kv@", targetTemplate: "@other.status@", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewTemplateParser().SetTemplate(tt.sourceTemplate, tt.targetTemplate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,11 +1,19 @@
 package parser
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/gitKashish/golog/internal/core/color"
 	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/presets"
+	"github.com/gitKashish/golog/internal/core/stacktrace"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,9 +27,51 @@ type LogParser interface {
 	SetTemplate(sourceTemplate, targetTemplate string) error
 }
 
-// TemplateParser implements the LogParser interface
+// RecordParser defines the interface for parsers that expose parsed field values
+type RecordParser interface {
+	LogParser
+	// ParseRecord parses a log entry into a record
+	ParseRecord(sourceLog string) *models.Record
+	// ParseRecords parses log lines into records, entries may span multiple lines
+	ParseRecords(lines []string) ([]*models.Record, error)
+	// SingleLine reports whether every entry is a single line, so that lines can be parsed independently
+	SingleLine() bool
+	// Render formats a parsed record using the target template
+	Render(record *models.Record) string
+}
+
+// maxFieldWidth is the largest width a fixed-width field may specify
+const maxFieldWidth = 1000
+
+// Regexes finding the fields of source and target templates
+var (
+	sourceFieldRegex = regexp.MustCompile(`@(\w+)-(\w+(?:\+\w+)?)(?:\{(\d+)\})?@`)
+	targetFieldRegex = regexp.MustCompile(`@([\w.]+)(?::([\w.+]+))?@`)
+)
+
+// escapedAt is written in templates for a literal `@`, and maskedAt replaces it
+// while looking for fields so that offsets into the template stay the same
+const (
+	escapedAt = `\@`
+	maskedAt  = "\x00\x00"
+)
+
+// Styler styles the parts of rendered output, like a color.Painter
+type Styler interface {
+	// Field styles the value of a field, modifier is the style given after its
+	// name in the target template, like `red` in @message:red@
+	Field(field *models.Field, modifier, value string) string
+	// Text styles literal text of the target template, including its markup
+	Text(text string) string
+}
+
+// TemplateParser implements the LogParser interface. Once its template is set,
+// parsing only reads the template and its compiled regexes, so a parser may be
+// shared by goroutines parsing lines concurrently.
 type TemplateParser struct {
-	template *models.Template
+	template    *models.Template
+	styler      Styler   // Styles rendered output, nil for plain text
+	vendorPaths []string // Paths of folded stack trace frames, stacktrace.DefaultVendorPaths if nil
 }
 
 // NewTemplateParser creates a new TemplateParser
@@ -33,8 +83,58 @@ func NewTemplateParser() *TemplateParser {
 
 // Parse parses a log entry using the template
 func (p *TemplateParser) Parse(sourceLog string) string {
-	if p.template == nil || p.template.SourceRegex == nil {
-		return sourceLog
+	return p.ParseRecord(sourceLog).Output
+}
+
+// ParseRecord parses a log entry into a record holding the captured field values.
+// The record's output is the rendered target template, or the source log as is
+// if it does not match the template.
+func (p *TemplateParser) ParseRecord(sourceLog string) *models.Record {
+	var record *models.Record
+	switch p.template.Literals.Format {
+	case models.FormatJSON:
+		record = p.parseJSONRecord(sourceLog)
+	case models.FormatLogfmt:
+		record = parseLogfmtRecord(sourceLog)
+	case models.FormatCSV, models.FormatTSV, models.FormatDelimited:
+		record = p.parseDelimitedRecord(sourceLog)
+	default:
+		record = p.parseTemplateRecord(sourceLog)
+	}
+
+	if record.Matched {
+		record.Output = p.Render(record)
+	}
+	return record
+}
+
+// ParseRecords parses log lines into records numbered by the line they start on.
+// Unlike ParseRecord, a single entry may span multiple lines, like delimited rows
+// with quoted line breaks.
+func (p *TemplateParser) ParseRecords(lines []string) ([]*models.Record, error) {
+	if models.IsDelimited(p.template.Literals.Format) {
+		return p.parseDelimitedRecords(lines)
+	}
+
+	records := make([]*models.Record, len(lines))
+	for i, line := range lines {
+		records[i] = p.ParseRecord(line)
+		records[i].Line = i + 1
+	}
+	return records, nil
+}
+
+// SingleLine reports whether every entry is a single line. Delimited rows may
+// span lines with quoted line breaks and depend on a header read first.
+func (p *TemplateParser) SingleLine() bool {
+	return !models.IsDelimited(p.template.Literals.Format)
+}
+
+// parseTemplateRecord parses a log entry using the source template
+func (p *TemplateParser) parseTemplateRecord(sourceLog string) *models.Record {
+	record := models.NewRecord(0, sourceLog)
+	if p.template.SourceRegex == nil {
+		return record
 	}
 
 	// Retrieve field values from source log
@@ -42,32 +142,206 @@ func (p *TemplateParser) Parse(sourceLog string) string {
 
 	// Check if log matches format or not
 	if len(fields) <= 1 {
-		return sourceLog
+		return record
 	}
 
 	// Map Field Names and their corresponding values
-	fieldValues := map[string]string{}
 	count := 1
 	for _, field := range p.template.Fields {
 		if count < len(fields) {
-			fieldValues[field.Name] = field.Format(fields[count])
+			value := fields[count]
+			if field.Width > 0 {
+				value = trimValue(value, p.template.Literals.Trim)
+			}
+			record.Set(field, value)
+			p.addSubFields(field, value, record)
 			count++
 		}
 	}
 
-	formattedLog := p.template.Literals.Target
+	record.Matched = true
+	return p.checkTypes(record)
+}
+
+// checkTypes returns an unmatched record in place of a record holding a value
+// its field type rejects, if the template has strict types
+func (p *TemplateParser) checkTypes(record *models.Record) *models.Record {
+	if !p.template.Literals.StrictTypes || !record.Matched {
+		return record
+	}
 	for _, field := range p.template.Fields {
-		fieldRegex := regexp.MustCompile("@" + field.Name + "@")
-		if value, ok := fieldValues[field.Name]; ok {
-			formattedLog = fieldRegex.ReplaceAllString(formattedLog, value)
+		value, ok := record.Value(field.Name)
+		if !ok {
+			continue
+		}
+		if _, err := field.Coerce(value); err != nil {
+			return models.NewRecord(record.Line, record.Raw)
 		}
 	}
-	return formattedLog
+	return record
+}
+
+// addSubFields adds the named parts of a structured field value to a record
+// as fields like "details.code"
+func (p *TemplateParser) addSubFields(field *models.Field, value string, record *models.Record) {
+	switch field.Type {
+	case models.JSON:
+		trimmed := strings.TrimSpace(value)
+		if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+			p.flattenJSON(json.RawMessage(trimmed), field.Name+".", record)
+		}
+	case models.KV:
+		if pairs, ok := ParseKeyValues(strings.TrimSpace(value)); ok {
+			addKeyValues(pairs, field.Name+".", record)
+		}
+	case models.URL:
+		if parsed, err := models.ParseURL(value); err == nil {
+			addURLParts(parsed, field.Name+".", record)
+		}
+	case models.Stacktrace:
+		if trace, ok := stacktrace.Parse(value); ok {
+			if top, ok := trace.Top(p.stacktraceVendorPaths()); ok {
+				record.Set(&models.Field{Name: field.Name + ".top", Type: models.String}, top.String())
+			}
+		}
+	case models.Base64, models.URLEncoded, models.Quoted:
+		if decoded, err := field.Decode(value); err == nil {
+			p.addSubFields(&models.Field{Name: field.Name, Type: field.Decoded}, decoded, record)
+		}
+	}
+}
+
+// Continue adds a continuation line of a multi-line entry, like a frame of a
+// stack trace, to the last field of the stacktrace type of a record and renders
+// the record again. It is false if the record has no such field.
+func (p *TemplateParser) Continue(record *models.Record, line string) bool {
+	for i := len(p.template.Fields) - 1; i >= 0; i-- {
+		field := p.template.Fields[i]
+		value, ok := record.Value(field.Name)
+		if field.Type != models.Stacktrace || !ok {
+			continue
+		}
+		value += "\n" + line
+		record.Set(field, value)
+		p.addSubFields(field, value, record)
+		record.Output = p.Render(record)
+		return true
+	}
+	return false
+}
+
+// addURLParts adds the parts of a URL to a record as fields like "url.host"
+// and "url.query.id", holding the first value of each query parameter
+func addURLParts(parsed *url.URL, prefix string, record *models.Record) {
+	parts := []struct{ name, value string }{
+		{"scheme", parsed.Scheme},
+		{"host", parsed.Hostname()},
+		{"port", parsed.Port()},
+		{"path", parsed.Path},
+		{"query", parsed.RawQuery},
+		{"fragment", parsed.Fragment},
+	}
+	for _, part := range parts {
+		record.Set(&models.Field{Name: prefix + part.name, Type: models.String}, part.value)
+	}
+
+	query := parsed.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := prefix + "query." + kvKeyRegex.ReplaceAllString(key, "_")
+		record.Set(&models.Field{Name: name, Type: models.String}, query.Get(key))
+	}
+}
+
+// Render formats a parsed record using the target template. Style modifiers
+// and markup of the target template only apply with a styler, and are left out
+// of plain text.
+func (p *TemplateParser) Render(record *models.Record) string {
+	if p.template == nil || p.template.TargetFieldRegex == nil {
+		return record.Raw
+	}
+
+	var output strings.Builder
+	target := maskEscapes(p.template.Literals.Target)
+	end := 0
+	for _, match := range p.template.TargetFieldRegex.FindAllStringSubmatchIndex(target, -1) {
+		output.WriteString(p.text(target[end:match[0]]))
+		end = match[1]
+
+		// Fields missing from the record are left empty
+		name := target[match[2]:match[3]]
+		value := p.formatted(record, name)
+		if p.styler != nil {
+			field, _ := record.Field(name)
+			modifier := ""
+			if match[4] >= 0 {
+				modifier = target[match[4]:match[5]]
+			}
+			value = p.styler.Field(field, modifier, value)
+		}
+		output.WriteString(value)
+	}
+	output.WriteString(p.text(target[end:]))
+	return output.String()
+}
+
+// formatted returns the formatted value of a field of a record, empty if the
+// record lacks the field
+func (p *TemplateParser) formatted(record *models.Record, name string) string {
+	field, ok := record.Field(name)
+	if !ok {
+		return ""
+	}
+	value, _ := record.Value(name)
+	if p.vendorPaths != nil {
+		withPaths := *field
+		withPaths.VendorPaths = p.vendorPaths
+		return withPaths.Format(value)
+	}
+	return field.Format(value)
+}
+
+// text renders literal text of the target template
+func (p *TemplateParser) text(literal string) string {
+	literal = strings.ReplaceAll(literal, maskedAt, "@")
+	if p.styler != nil {
+		return p.styler.Text(literal)
+	}
+	return color.StripMarkup(literal)
+}
+
+// SetStyler sets the styler of rendered output, nil for plain text. The styler
+// may be shared by goroutines parsing concurrently.
+func (p *TemplateParser) SetStyler(styler Styler) {
+	p.styler = styler
+}
+
+// SetVendorPaths sets the paths of frames folded in stack traces and left out
+// of their top frame, stacktrace.DefaultVendorPaths if nil
+func (p *TemplateParser) SetVendorPaths(vendorPaths []string) {
+	p.vendorPaths = vendorPaths
+}
+
+// stacktraceVendorPaths returns the paths of folded stack trace frames
+func (p *TemplateParser) stacktraceVendorPaths() []string {
+	if p.vendorPaths == nil {
+		return stacktrace.DefaultVendorPaths
+	}
+	return p.vendorPaths
+}
+
+// Literals returns the template literals the parser was set up with
+func (p *TemplateParser) Literals() models.TemplateLiterals {
+	return p.template.Literals
 }
 
 // LoadTemplate loads a template from a file
 func (p *TemplateParser) LoadTemplate(templatePath string) error {
-	literals, err := loadTemplateLiterals(templatePath)
+	literals, err := LoadLiterals(templatePath)
 	if err != nil {
 		return err
 	}
@@ -75,6 +349,11 @@ func (p *TemplateParser) LoadTemplate(templatePath string) error {
 	return p.setupTemplate(literals)
 }
 
+// SetLiterals sets the template from template literals
+func (p *TemplateParser) SetLiterals(literals models.TemplateLiterals) error {
+	return p.setupTemplate(literals)
+}
+
 // SetTemplate sets the template directly
 func (p *TemplateParser) SetTemplate(sourceTemplate, targetTemplate string) error {
 	literals := models.TemplateLiterals{
@@ -90,9 +369,34 @@ func (p *TemplateParser) setupTemplate(literals models.TemplateLiterals) error {
 	p.template = models.NewTemplate()
 	p.template.Literals = literals
 
-	// Compile regex for fields in source and target template
-	p.template.SourceFieldRegex = regexp.MustCompile(`@(\w+)-(\w+)@`)
-	p.template.TargetFieldRegex = regexp.MustCompile(`@(\w+)@`)
+	// Regexes for fields in source and target template
+	p.template.SourceFieldRegex = sourceFieldRegex
+	p.template.TargetFieldRegex = targetFieldRegex
+
+	if !isMismatchAction(literals.OnMismatch) {
+		return fmt.Errorf("invalid onMismatch `%s`, expected passthrough, drop, append-to-previous, mark or reject", literals.OnMismatch)
+	}
+
+	switch {
+	case literals.Format == "" || literals.Format == models.FormatTemplate:
+		if !isTrimMode(literals.Trim) {
+			return fmt.Errorf("invalid trim `%s`, expected both, left, right or none", literals.Trim)
+		}
+	case models.IsSourceless(literals.Format):
+		// Fields are discovered from every entry, so there is nothing to validate them against
+		if literals.Source != "" {
+			return fmt.Errorf("source template is not used with %s input", literals.Format)
+		}
+		if literals.Target == "" {
+			return fmt.Errorf("target template is required with %s input", literals.Format)
+		}
+		if models.IsDelimited(literals.Format) {
+			return p.setupDelimited()
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid input format `%s`", literals.Format)
+	}
 
 	// Parse template literal and retrieve fields
 	if err := p.parseSourceTemplate(); err != nil {
@@ -104,59 +408,188 @@ func (p *TemplateParser) setupTemplate(literals models.TemplateLiterals) error {
 	}
 
 	// Get regex to parse logs
-	p.template.SourceRegex = getSourceRegex(p.template.Literals.Source, *p.template.SourceFieldRegex)
+	p.template.SourceRegex = getSourceRegex(p.template.Literals.Source)
 	return nil
 }
 
 // parseSourceTemplate parses the source template and extracts fields
 func (p *TemplateParser) parseSourceTemplate() error {
+	source := p.template.Literals.Source
+
 	// Extract fields from literal
-	matches := p.template.SourceFieldRegex.FindAllStringSubmatch(p.template.Literals.Source, -1)
+	matches := p.template.SourceFieldRegex.FindAllStringSubmatchIndex(maskEscapes(source), -1)
 	for _, match := range matches {
+		name := source[match[2]:match[3]]
+		typeName := source[match[4]:match[5]]
+
 		// Check if a field name was already mentioned in log
-		if p.template.FieldNames[match[1]] {
-			return fmt.Errorf("duplicate field name `%s` in source template", match[1])
+		if p.template.FieldNames[name] {
+			return newTemplateError("source", source, match[2], match[3],
+				fmt.Sprintf("duplicate field name `%s` in source template", name))
 		}
-		p.template.FieldNames[match[1]] = true
-		fieldType, err := models.FieldTypeFromString(match[2]) // Get corresponding FieldType
+		p.template.FieldNames[name] = true
+		fieldType, decoded, err := models.ParseTypeName(typeName) // Get corresponding FieldType
 		if err != nil {
-			return err
+			return newTemplateError("source", source, match[4], match[5], err.Error())
+		}
+
+		// Optional width for fixed-width columns
+		width := 0
+		if match[6] >= 0 {
+			width, _ = strconv.Atoi(source[match[6]:match[7]])
+			if width <= 0 || width > maxFieldWidth {
+				return newTemplateError("source", source, match[6], match[7],
+					fmt.Sprintf("invalid width `%s` for field `%s`, expected 1 to %d", source[match[6]:match[7]], name, maxFieldWidth))
+			}
 		}
-		p.template.Fields = append(p.template.Fields, &models.Field{Name: match[1], Type: fieldType}) // Add new field
+		p.template.Fields = append(p.template.Fields, &models.Field{Name: name, Type: fieldType, Width: width, Decoded: decoded}) // Add new field
 	}
 	return nil
 }
 
+// trimValue removes the padding of a fixed-width field value
+func trimValue(value, trim string) string {
+	switch trim {
+	case models.TrimNone:
+		return value
+	case models.TrimLeft:
+		return strings.TrimLeft(value, " \t")
+	case models.TrimRight:
+		return strings.TrimRight(value, " \t")
+	default:
+		return strings.Trim(value, " \t")
+	}
+}
+
+// isTrimMode checks whether a trim mode is valid, empty defaults to trimming both sides
+func isTrimMode(trim string) bool {
+	switch trim {
+	case "", models.TrimBoth, models.TrimLeft, models.TrimRight, models.TrimNone:
+		return true
+	}
+	return false
+}
+
+// isMismatchAction checks whether an action on unmatched entries is valid, empty
+// defaults to passing them through
+func isMismatchAction(action string) bool {
+	switch action {
+	case "", models.MismatchPassthrough, models.MismatchDrop, models.MismatchAppend, models.MismatchMark, models.MismatchReject:
+		return true
+	}
+	return false
+}
+
 // parseTargetTemplate parses the target template and validates it
 func (p *TemplateParser) parseTargetTemplate() error {
+	target := p.template.Literals.Target
+
 	// Extract fields from literal
-	matches := p.template.TargetFieldRegex.FindAllStringSubmatch(p.template.Literals.Target, -1)
+	matches := p.template.TargetFieldRegex.FindAllStringSubmatchIndex(maskEscapes(target), -1)
 	for _, match := range matches {
-		if !p.template.FieldNames[match[1]] {
-			return fmt.Errorf("field `%s` not found in source template", match[1])
+		name := target[match[2]:match[3]]
+		if match[4] >= 0 && !color.IsStyle(target[match[4]:match[5]]) {
+			return newTemplateError("target", target, match[4], match[5],
+				fmt.Sprintf("unknown style `%s` of field `%s`", target[match[4]:match[5]], name))
+		}
+
+		// Sub-fields are validated against the structured field they belong to
+		if root, _, ok := strings.Cut(name, "."); ok {
+			field := p.template.Field(root)
+			if field == nil || !field.HasSubFields() {
+				return newTemplateError("target", target, match[2], match[3],
+					fmt.Sprintf("field `%s` not found in source template, `%s` has no sub-fields", name, root))
+			}
+			continue
+		}
+
+		if !p.template.FieldNames[name] {
+			return newTemplateError("target", target, match[2], match[3],
+				fmt.Sprintf("field `%s` not found in source template", name))
 		}
 	}
 	return nil
 }
 
+// sourceSegment is a literal or field part of a source template
+type sourceSegment struct {
+	field   string // Name of the field, empty for literal text
+	text    string // Text of the segment in the source template
+	width   int    // Number of characters of a fixed-width field, 0 if not fixed
+	pattern string // Pattern matching the segment, fields capture their value
+}
+
 // getSourceRegex creates a regex for extracting field values from source logs
-func getSourceRegex(sourceTemplate string, sourceFieldRegex regexp.Regexp) *regexp.Regexp {
-	sourceTemplate = regexp.QuoteMeta(sourceTemplate) // Escape all regex meta-characters
+func getSourceRegex(sourceTemplate string) *regexp.Regexp {
+	// Build the pattern from literal segments and field capture groups
+	regexPattern := strings.Builder{}
+	for _, segment := range sourceSegments(sourceTemplate) {
+		regexPattern.WriteString(segment.pattern)
+	}
+
+	// Compile the regex with start and end anchors
+	sourceRegex := regexp.MustCompile("^" + regexPattern.String() + "$")
+	return sourceRegex
+}
+
+// sourceSegments splits a source template into its literal and field segments,
+// leaving out empty literals between adjacent fields
+func sourceSegments(sourceTemplate string) []sourceSegment {
+	matches := sourceFieldRegex.FindAllStringSubmatchIndex(maskEscapes(sourceTemplate), -1)
+
+	segments := []sourceSegment{}
+	addLiteral := func(literal string, adjacentWidth bool) {
+		if literal != "" {
+			segments = append(segments, sourceSegment{text: literal, pattern: literalPattern(literal, adjacentWidth)})
+		}
+	}
+
+	last := 0
+	for i, match := range matches {
+		adjacentWidth := hasWidth(match) || (i > 0 && hasWidth(matches[i-1]))
+		addLiteral(sourceTemplate[last:match[0]], adjacentWidth)
+
+		segment := sourceSegment{field: sourceTemplate[match[2]:match[3]], text: sourceTemplate[match[0]:match[1]], pattern: `(.*?)`}
+		if hasWidth(match) {
+			// Width-specified fields capture exactly that many characters
+			segment.width, _ = strconv.Atoi(sourceTemplate[match[6]:match[7]])
+			segment.pattern = `(.{` + sourceTemplate[match[6]:match[7]] + `})`
+		}
+		segments = append(segments, segment)
+		last = match[1]
+	}
+	adjacentWidth := len(matches) > 0 && hasWidth(matches[len(matches)-1])
+	addLiteral(sourceTemplate[last:], adjacentWidth)
+	return segments
+}
 
-	// Replace field patterns with capture groups
-	regexPattern := sourceFieldRegex.ReplaceAllString(sourceTemplate, `(.*?)`)
+// literalPattern creates the pattern matching a literal part of the source template.
+// Whitespace is matched flexibly, unless the literal borders a width-specified
+// field whose padding would otherwise be consumed.
+func literalPattern(literal string, exact bool) string {
+	literal = strings.ReplaceAll(literal, escapedAt, "@")
+	literal = regexp.QuoteMeta(literal) // Escape all regex meta-characters
+	if exact {
+		return literal
+	}
 
 	// Replace whitespace with flexible whitespace pattern
 	spaceRegex := regexp.MustCompile(`\s+`)
-	regexPattern = spaceRegex.ReplaceAllString(regexPattern, `\s+`)
+	return spaceRegex.ReplaceAllString(literal, `\s+`)
+}
 
-	// Compile the regex with start and end anchors
-	sourceRegex := regexp.MustCompile("^" + regexPattern + "$")
-	return sourceRegex
+// maskEscapes hides escaped `@` characters of a template from the field regexes
+func maskEscapes(literal string) string {
+	return strings.ReplaceAll(literal, escapedAt, maskedAt)
+}
+
+// hasWidth checks whether a source field match specifies a width
+func hasWidth(match []int) bool {
+	return len(match) > 7 && match[6] >= 0
 }
 
-// loadTemplateLiterals loads template literals from a file
-func loadTemplateLiterals(templatePath string) (models.TemplateLiterals, error) {
+// LoadLiterals loads template literals from a file
+func LoadLiterals(templatePath string) (models.TemplateLiterals, error) {
 	literals := models.TemplateLiterals{}
 
 	// Read template file
@@ -171,5 +604,40 @@ func loadTemplateLiterals(templatePath string) (models.TemplateLiterals, error)
 		return literals, fmt.Errorf("error parsing template file: %w", err)
 	}
 
+	if literals.Extends != "" {
+		return extendPreset(literals)
+	}
 	return literals, nil
 }
+
+// extendPreset fills the parts missing from template literals with the preset they extend
+func extendPreset(literals models.TemplateLiterals) (models.TemplateLiterals, error) {
+	preset, ok := presets.Get(literals.Extends)
+	if !ok {
+		return literals, fmt.Errorf("unknown preset `%s` in extends", literals.Extends)
+	}
+
+	extended := preset.Literals
+	extended.Extends = literals.Extends
+	extended.Tests = literals.Tests
+	extended.OnMismatch = literals.OnMismatch
+	extended.Marker = literals.Marker
+	extended.Fallback = literals.Fallback
+	extended.StrictTypes = extended.StrictTypes || literals.StrictTypes
+	if literals.Trim != "" {
+		extended.Trim = literals.Trim
+	}
+	if literals.Format != "" {
+		extended.Format = literals.Format
+		extended.Delimiter = literals.Delimiter
+		extended.Columns = literals.Columns
+		extended.Header = literals.Header
+	}
+	if literals.Source != "" {
+		extended.Source = literals.Source
+	}
+	if literals.Target != "" {
+		extended.Target = literals.Target
+	}
+	return extended, nil
+}
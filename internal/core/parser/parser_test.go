@@ -2,8 +2,12 @@ package parser
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+	"github.com/gitKashish/golog/internal/core/presets"
 )
 
 // TestTemplateParser_Parse_Simple tests the parser with a simple test case
@@ -130,3 +134,374 @@ func TestTemplateParser_SetTemplate_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateParser_ParseRecord(t *testing.T) {
+	p := NewTemplateParser()
+	err := p.SetTemplate("@level-string@ @module-string@ @message-string@", "[@level@] @module@: @message@")
+	if err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	record := p.ParseRecord("INFO users created user $1")
+	if !record.Matched {
+		t.Fatalf("ParseRecord() did not match")
+	}
+	if value, _ := record.Value("module"); value != "users" {
+		t.Errorf("ParseRecord() module = %v, want users", value)
+	}
+	if want := "[INFO] users: created user $1"; record.Output != want {
+		t.Errorf("ParseRecord() output = %v, want %v", record.Output, want)
+	}
+
+	record = p.ParseRecord("unmatched")
+	if record.Matched || record.Output != "unmatched" {
+		t.Errorf("ParseRecord() = %+v, want unmatched record with raw output", record)
+	}
+}
+
+// TestPresets_Examples checks that every built-in preset matches its own example
+func TestPresets_Examples(t *testing.T) {
+	for _, preset := range presets.List() {
+		t.Run(preset.Name, func(t *testing.T) {
+			p := NewTemplateParser()
+			if err := p.SetLiterals(preset.Literals); err != nil {
+				t.Fatalf("Failed to set template: %v", err)
+			}
+
+			record := p.ParseRecord(preset.Example)
+			if !record.Matched {
+				t.Errorf("ParseRecord(%q) did not match", preset.Example)
+			}
+		})
+	}
+}
+
+func TestTemplateParser_LoadTemplate_Extends(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		log        string
+		want       string
+		wantPreset string // Canonical name of the extended preset, not checked if empty
+		wantErr    bool
+	}{
+		{
+			name:    "Override target",
+			content: "extends: nginx-combined\ntargetTemplate: \"@status@ @request@\"\n",
+			log:     `203.0.113.7 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 404 0 "-" "curl/8.0"`,
+			want:    "404 GET / HTTP/1.1",
+		},
+		{
+			name:       "Alias",
+			content:    "extends: nginx\n",
+			log:        `203.0.113.7 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 404 0 "-" "curl/8.0"`,
+			want:       `[10/Oct/2000:13:55:36 -0700] 203.0.113.7 "GET / HTTP/1.1" 404 0 "curl/8.0"`,
+			wantPreset: "nginx-combined",
+		},
+		{
+			name:    "Unknown preset",
+			content: "extends: unknown\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templatePath := filepath.Join(t.TempDir(), "template.yaml")
+			if err := os.WriteFile(templatePath, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("Failed to write template: %v", err)
+			}
+
+			p := NewTemplateParser()
+			err := p.LoadTemplate(templatePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := p.Parse(tt.log); got != tt.want {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+			if tt.wantPreset != "" {
+				preset, _ := presets.Get(tt.wantPreset)
+				if got := p.Literals(); got.Source != preset.Literals.Source || got.Target != preset.Literals.Target {
+					t.Errorf("LoadTemplate() literals = %+v, want the %s preset", got, tt.wantPreset)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateParser_Parse_FixedWidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		target    string
+		trim      string
+		sourceLog string
+		want      string
+	}{
+		{
+			name:      "Adjacent fields",
+			source:    "@date-string{10}@@host-string{8}@@code-number{4}@ @message-string@",
+			target:    "@date@|@host@|@code@|@message@",
+			sourceLog: "2024-01-02web01     42 job finished",
+			want:      "2024-01-02|web01|42|job finished",
+		},
+		{
+			name:      "Keep padding",
+			source:    "@host-string{8}@@code-number{4}@",
+			target:    "[@host@][@code@]",
+			trim:      models.TrimNone,
+			sourceLog: "web01     42",
+			want:      "[web01   ][  42]",
+		},
+		{
+			name:      "Trim right only",
+			source:    "@host-string{8}@@code-number{4}@",
+			target:    "[@host@][@code@]",
+			trim:      models.TrimRight,
+			sourceLog: " web01    42",
+			want:      "[ web01][  42]",
+		},
+		{
+			name:      "Wrong length",
+			source:    "@host-string{8}@@code-number{4}@",
+			target:    "[@host@][@code@]",
+			sourceLog: "web01 42",
+			want:      "web01 42",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTemplateParser()
+			err := p.SetLiterals(models.TemplateLiterals{Source: tt.source, Target: tt.target, Trim: tt.trim})
+			if err != nil {
+				t.Fatalf("Failed to set template: %v", err)
+			}
+
+			if got := p.Parse(tt.sourceLog); got != tt.want {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateParser_SetLiterals_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		literals models.TemplateLiterals
+	}{
+		{name: "Zero width", literals: models.TemplateLiterals{Source: "@a-string{0}@", Target: "@a@"}},
+		{name: "Huge width", literals: models.TemplateLiterals{Source: "@a-string{5000}@", Target: "@a@"}},
+		{name: "Invalid trim", literals: models.TemplateLiterals{Source: "@a-string{3}@", Target: "@a@", Trim: "middle"}},
+		{name: "Invalid onMismatch", literals: models.TemplateLiterals{Source: "@a-string@", Target: "@a@", OnMismatch: "ignore"}},
+		{name: "Unknown style", literals: models.TemplateLiterals{Source: "@a-string@", Target: "@a:pink@"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewTemplateParser().SetLiterals(tt.literals); err == nil {
+				t.Errorf("SetLiterals() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestTemplateParser_Parse_EscapedAt(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetTemplate(`\@@user-string@ \@host-string@ @host-string@`, `@user@\@@host@`); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	if got, want := p.Parse("@bob @host-string@ example.com"), "bob@example.com"; got != want {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+}
+
+// bracketStyler wraps styled parts of the output in brackets
+type bracketStyler struct{}
+
+func (bracketStyler) Field(field *models.Field, modifier, value string) string {
+	return "<" + field.Name + ":" + modifier + ":" + value + ">"
+}
+
+func (bracketStyler) Text(text string) string {
+	return "(" + text + ")"
+}
+
+func TestTemplateParser_Render_Styled(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetTemplate("@level-string@ @message-string@", "{bold}[{/}@level:color@] {x} @message:red@"); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	// Modifiers and markup are left out of plain text, unknown tags are kept
+	if got, want := p.Parse("WARN disk full"), "[WARN] {x} disk full"; got != want {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+
+	p.SetStyler(bracketStyler{})
+	if got, want := p.Parse("WARN disk full"), "({bold}[{/})<level:color:WARN>(] {x} )<message:red:disk full>()"; got != want {
+		t.Errorf("Parse() with styler = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateParser_Parse_StrictTypes(t *testing.T) {
+	p := NewTemplateParser()
+	literals := models.TemplateLiterals{
+		Source:      "@client-ip@ @took-duration@ @size-bytes@",
+		Target:      "@client@ took @took@ for @size@",
+		StrictTypes: true,
+	}
+	if err := p.SetLiterals(literals); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	record := p.ParseRecord("10.0.0.1 1500ms 2048")
+	if want := "10.0.0.1 took 1.5s for 2 KiB"; !record.Matched || record.Output != want {
+		t.Errorf("ParseRecord() = %q, matched %v, want %q", record.Output, record.Matched, want)
+	}
+
+	// A value its type rejects makes the entry unmatched
+	record = p.ParseRecord("10.0.0.300 1500ms 2048")
+	if record.Matched || len(record.Names()) != 0 {
+		t.Errorf("ParseRecord() = %+v, want unmatched record without fields", record)
+	}
+
+	// Without strict types the value is rendered as is
+	literals.StrictTypes = false
+	if err := p.SetLiterals(literals); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+	if got, want := p.Parse("10.0.0.300 soon 2048"), "10.0.0.300 took soon for 2 KiB"; got != want {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateParser_Parse_StrictTypes_Timestamps(t *testing.T) {
+	p := NewTemplateParser()
+	literals := models.TemplateLiterals{
+		Source:      "@ts-timestamp@ @level-level@ @msg-string@",
+		Target:      "@ts@ @level@ @msg@",
+		StrictTypes: true,
+	}
+	if err := p.SetLiterals(literals); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	// Timestamps in any recognized layout satisfy strict types
+	for _, line := range []string{"2024-01-02T03:04:05Z INFO hello", "2024-01-02T03:04:05.123+02:00 INFO hello"} {
+		if record := p.ParseRecord(line); !record.Matched || record.Output != line {
+			t.Errorf("ParseRecord(%q) = %q, matched %v, want a matched record", line, record.Output, record.Matched)
+		}
+	}
+
+	if record := p.ParseRecord("yesterday INFO hello"); record.Matched {
+		t.Errorf("ParseRecord() matched an invalid timestamp")
+	}
+}
+
+func TestTemplateParser_ParseRecord_URL(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetTemplate("GET @url-url@", "@url.host@ @url.path@ @url.query.user_id@"); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	record := p.ParseRecord("GET https://example.com:8443/users/1?user-id=7&tab=posts#top")
+	want := map[string]string{
+		"url.scheme":        "https",
+		"url.host":          "example.com",
+		"url.port":          "8443",
+		"url.path":          "/users/1",
+		"url.query":         "user-id=7&tab=posts",
+		"url.query.user_id": "7",
+		"url.query.tab":     "posts",
+		"url.fragment":      "top",
+	}
+	for name, wantValue := range want {
+		if value, _ := record.Value(name); value != wantValue {
+			t.Errorf("ParseRecord() %s = %q, want %q", name, value, wantValue)
+		}
+	}
+	if want := "example.com /users/1 7"; record.Output != want {
+		t.Errorf("ParseRecord() output = %q, want %q", record.Output, want)
+	}
+}
+
+func TestTemplateParser_ParseRecord_Decoded(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetTemplate("@path-urlencoded@ @body-base64+json@ @note-quoted@", "@path@ @body.user.id@ @note@"); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	// {"user":{"id":7}}
+	record := p.ParseRecord(`%2Fusers%2F7 eyJ1c2VyIjp7ImlkIjo3fX0= "two\nlines"`)
+	if want := "/users/7 7 two\nlines"; record.Output != want {
+		t.Errorf("ParseRecord() output = %q, want %q", record.Output, want)
+	}
+	if formatted, _ := record.Formatted("body"); formatted != "{\n  \"user\": {\n    \"id\": 7\n  }\n}" {
+		t.Errorf("ParseRecord() body = %q, want pretty-printed JSON", formatted)
+	}
+
+	// Values that can't be decoded are rendered as they are
+	record = p.ParseRecord(`100% e30!! bad\q`)
+	if want := "100%  bad\\q"; record.Output != want {
+		t.Errorf("ParseRecord() output = %q, want %q", record.Output, want)
+	}
+}
+
+func TestTemplateParser_Continue(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetTemplate("@level-string@ @trace-stacktrace@", "[@level@] @trace.top@: @trace@"); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+
+	record := p.ParseRecord("ERROR TypeError: x is undefined")
+	for _, line := range []string{"  at Layer.handle (/app/node_modules/express/layer.js:95:5)", "  at handler (/app/index.js:10:5)"} {
+		if !p.Continue(record, line) {
+			t.Fatalf("Continue() = false, want the line added to the trace")
+		}
+	}
+
+	// Vendor frames are folded and the top frame is the innermost one of the application
+	want := "[ERROR] handler (/app/index.js:10:5): TypeError: x is undefined\n    … 1 frame hidden\n    at handler (/app/index.js:10:5)"
+	if record.Output != want {
+		t.Errorf("Continue() output = %q, want %q", record.Output, want)
+	}
+
+	if err := p.SetTemplate("@level-string@ @message-string@", "@message@"); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+	if p.Continue(p.ParseRecord("ERROR boom"), "  at handler (/app/index.js:10:5)") {
+		t.Errorf("Continue() = true for a record without a stacktrace field")
+	}
+}
+
+func TestTemplateParser_SetVendorPaths(t *testing.T) {
+	p := NewTemplateParser()
+	if err := p.SetLiterals(models.TemplateLiterals{Format: models.FormatJSON, Target: "@trace.top@: @trace@"}); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+	line := `{"trace":"TypeError: x\n    at Layer.handle (/app/lib/layer.js:95:5)\n    at handler (/app/index.js:10:5)"}`
+
+	// Frames of the configured vendor paths are folded and left out of the top frame
+	p.SetVendorPaths([]string{"/app/lib/"})
+	want := "handler (/app/index.js:10:5): TypeError: x\n    … 1 frame hidden\n    at handler (/app/index.js:10:5)"
+	if got := p.Parse(line); got != want {
+		t.Errorf("Parse() = %q, want %q", got, want)
+	}
+
+	// Other parsers keep the default vendor paths
+	other := NewTemplateParser()
+	if err := other.SetLiterals(p.Literals()); err != nil {
+		t.Fatalf("Failed to set template: %v", err)
+	}
+	want = "Layer.handle (/app/lib/layer.js:95:5): TypeError: x\n    at Layer.handle (/app/lib/layer.js:95:5)\n    at handler (/app/index.js:10:5)"
+	if got := other.Parse(line); got != want {
+		t.Errorf("Parse() with default vendor paths = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,283 @@
+package patterns
+
+import (
+	"container/list"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tokens replacing variable parts of messages
+const (
+	Wildcard = "<*>"
+	IPToken  = "<IP>"
+	NumToken = "<NUM>"
+)
+
+// DefaultSimilarity is the share of tokens a message must share with a pattern to join it
+const DefaultSimilarity = 0.5
+
+// DefaultMaxClusters is the number of patterns kept before the least recently seen is dropped
+const DefaultMaxClusters = 1000
+
+// maxChildren limits the first token branches per message length
+const maxChildren = 100
+
+var (
+	ipRegex  = regexp.MustCompile(`^(\d{1,3}\.){3}\d{1,3}(:\d+)?$`)
+	numRegex = regexp.MustCompile(`^[-+]?(\d+([.,:]\d+)*|0x[0-9a-fA-F]+)(ms|s|us|ns|%|[kKMG]i?B)?$`)
+)
+
+// Options configures a Miner
+type Options struct {
+	Similarity  float64 // Share of tokens a message must share with a pattern, DefaultSimilarity if 0
+	MaxClusters int     // Patterns kept in memory besides seeded ones, DefaultMaxClusters if 0
+}
+
+// Cluster is a group of messages sharing a pattern
+type Cluster struct {
+	ID      int
+	Tokens  []string  // Pattern tokens, variable positions are Wildcard
+	Count   int       // Number of messages in the cluster
+	First   time.Time // Earliest timestamp of the messages, zero if unknown
+	Last    time.Time // Latest timestamp of the messages, zero if unknown
+	Example string    // First line added to the cluster
+
+	key     string        // First token branch of the cluster's group
+	element *list.Element // Position in the recently seen list, nil for seeded clusters
+}
+
+// Pattern returns the pattern of a cluster as text
+func (c *Cluster) Pattern() string {
+	return strings.Join(c.Tokens, " ")
+}
+
+// Miner clusters messages into patterns following the Drain algorithm: messages are
+// routed by their token count and first token to a small group of clusters, and join
+// the most similar one or start a new cluster. Only the most recently seen clusters
+// are kept so memory stays bounded on large inputs, besides seeded clusters.
+type Miner struct {
+	options Options
+	groups  map[int]map[string][]*Cluster // Clusters by token count and first token
+	recent  *list.List                    // Clusters from most to least recently seen
+	seeded  []*Cluster                    // Clusters of known patterns, never evicted
+	nextID  int
+	evicted int
+}
+
+// NewMiner creates a new Miner
+func NewMiner(options Options) *Miner {
+	if options.Similarity <= 0 {
+		options.Similarity = DefaultSimilarity
+	}
+	if options.MaxClusters <= 0 {
+		options.MaxClusters = DefaultMaxClusters
+	}
+	return &Miner{
+		options: options,
+		groups:  make(map[int]map[string][]*Cluster),
+		recent:  list.New(),
+	}
+}
+
+// Add adds a message to its cluster and returns the cluster. The timestamp may be
+// zero if unknown, and the example is the line shown for new clusters.
+func (m *Miner) Add(message string, timestamp time.Time, example string) *Cluster {
+	tokens := Tokenize(message)
+	branches := m.branches(len(tokens))
+	key := branchKey(tokens, branches)
+
+	cluster := m.match(branches[key], tokens)
+	if cluster == nil {
+		cluster = m.newCluster(tokens, key, example)
+	} else {
+		for i, token := range tokens {
+			if cluster.Tokens[i] != token {
+				cluster.Tokens[i] = Wildcard
+			}
+		}
+		if cluster.element != nil {
+			m.recent.MoveToFront(cluster.element)
+		}
+	}
+
+	cluster.Count++
+	if !timestamp.IsZero() {
+		if cluster.First.IsZero() || timestamp.Before(cluster.First) {
+			cluster.First = timestamp
+		}
+		if timestamp.After(cluster.Last) {
+			cluster.Last = timestamp
+		}
+	}
+	return cluster
+}
+
+// Seed adds a cluster for a known pattern without counting a message, so that
+// later messages of the pattern join it. Seeded clusters are never evicted,
+// don't count towards MaxClusters and only take messages following the pattern.
+func (m *Miner) Seed(pattern string, example string) *Cluster {
+	tokens := strings.Fields(pattern)
+	cluster := m.addCluster(tokens, branchKey(tokens, m.branches(len(tokens))), example)
+	m.seeded = append(m.seeded, cluster)
+	return cluster
+}
+
+// Clusters returns all clusters sorted by decreasing count
+func (m *Miner) Clusters() []*Cluster {
+	clusters := make([]*Cluster, 0, len(m.seeded)+m.recent.Len())
+	clusters = append(clusters, m.seeded...)
+	for element := m.recent.Front(); element != nil; element = element.Next() {
+		clusters = append(clusters, element.Value.(*Cluster))
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].ID < clusters[j].ID
+	})
+	return clusters
+}
+
+// Evicted returns the number of clusters dropped to stay within MaxClusters
+func (m *Miner) Evicted() int {
+	return m.evicted
+}
+
+// Tokenize splits a message into whitespace separated tokens, masking numbers and IP addresses
+func Tokenize(message string) []string {
+	tokens := strings.Fields(message)
+	for i, token := range tokens {
+		tokens[i] = Mask(token)
+	}
+	return tokens
+}
+
+// Mask replaces a token holding an IP address or a number by its placeholder
+func Mask(token string) string {
+	// Keep punctuation around the value like in "(10ms)," or "id=42"
+	start := strings.LastIndexAny(token, "=([{<'\"") + 1
+	end := start + len(strings.TrimRight(token[start:], ")]}>'\",;"))
+	if start >= end {
+		return token
+	}
+
+	switch value := token[start:end]; {
+	case ipRegex.MatchString(value) || (strings.Count(value, ":") >= 2 && net.ParseIP(value) != nil):
+		return token[:start] + IPToken + token[end:]
+	case numRegex.MatchString(value):
+		return token[:start] + NumToken + token[end:]
+	default:
+		return token
+	}
+}
+
+// branches returns the groups of messages with the given number of tokens by first token
+func (m *Miner) branches(length int) map[string][]*Cluster {
+	branches := m.groups[length]
+	if branches == nil {
+		branches = make(map[string][]*Cluster)
+		m.groups[length] = branches
+	}
+	return branches
+}
+
+// newCluster adds a cluster to the group of a branch, dropping the least recently seen
+// clusters if there are too many
+func (m *Miner) newCluster(tokens []string, key, example string) *Cluster {
+	cluster := m.addCluster(tokens, key, example)
+	cluster.element = m.recent.PushFront(cluster)
+	m.evict()
+	return cluster
+}
+
+// addCluster adds a cluster to the group of a branch
+func (m *Miner) addCluster(tokens []string, key, example string) *Cluster {
+	m.nextID++
+	cluster := &Cluster{ID: m.nextID, Tokens: tokens, Example: example, key: key}
+	branches := m.groups[len(tokens)]
+	branches[key] = append(branches[key], cluster)
+	return cluster
+}
+
+// branchKey returns the first token branch of a message. Tokens holding digits
+// are likely variable, so they share a wildcard branch like tokens past maxChildren.
+func branchKey(tokens []string, branches map[string][]*Cluster) string {
+	if len(tokens) == 0 || strings.ContainsAny(tokens[0], "0123456789") {
+		return Wildcard
+	}
+	if _, exists := branches[tokens[0]]; !exists && len(branches) >= maxChildren {
+		return Wildcard
+	}
+	return tokens[0]
+}
+
+// match returns the most similar cluster of a group, nil if none is similar enough.
+// Seeded clusters hold known patterns, so they only match messages following
+// them exactly and are never generalized.
+func (m *Miner) match(clusters []*Cluster, tokens []string) *Cluster {
+	var best *Cluster
+	bestScore, bestWildcards := -1.0, -1
+	for _, cluster := range clusters {
+		score, wildcards := similarity(cluster.Tokens, tokens)
+		if cluster.element == nil && !follows(cluster.Tokens, tokens) {
+			continue
+		}
+		if score > bestScore || (score == bestScore && wildcards > bestWildcards) {
+			best, bestScore, bestWildcards = cluster, score, wildcards
+		}
+	}
+	if best == nil || bestScore < m.options.Similarity {
+		return nil
+	}
+	return best
+}
+
+// similarity returns the share of pattern tokens equal to the message tokens and
+// the number of wildcards in the pattern
+func similarity(pattern, tokens []string) (float64, int) {
+	if len(pattern) == 0 {
+		return 1, 0
+	}
+	equal, wildcards := 0, 0
+	for i, token := range pattern {
+		switch {
+		case token == Wildcard:
+			wildcards++
+		case token == tokens[i]:
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(pattern)), wildcards
+}
+
+// follows checks whether message tokens follow a pattern, equal to it but at wildcards
+func follows(pattern, tokens []string) bool {
+	for i, token := range pattern {
+		if token != Wildcard && token != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// evict drops the least recently seen clusters above MaxClusters
+func (m *Miner) evict() {
+	for m.recent.Len() > m.options.MaxClusters {
+		cluster := m.recent.Remove(m.recent.Back()).(*Cluster)
+		branches := m.groups[len(cluster.Tokens)]
+		group := branches[cluster.key]
+		for i, c := range group {
+			if c == cluster {
+				branches[cluster.key] = append(group[:i], group[i+1:]...)
+				break
+			}
+		}
+		if len(branches[cluster.key]) == 0 {
+			delete(branches, cluster.key)
+		}
+		m.evicted++
+	}
+}
@@ -0,0 +1,151 @@
+package patterns
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{token: "42", want: NumToken},
+		{token: "-3.5", want: NumToken},
+		{token: "1200ms", want: NumToken},
+		{token: "0x1f", want: NumToken},
+		{token: "id=42,", want: "id=" + NumToken + ","},
+		{token: "(10ms)", want: "(" + NumToken + ")"},
+		{token: "10.0.0.1", want: IPToken},
+		{token: "10.0.0.1:8080", want: IPToken},
+		{token: "[2001:db8::1]", want: "[" + IPToken + "]"},
+		{token: "10:00:01", want: NumToken},
+		{token: "alice", want: "alice"},
+		{token: "v2", want: "v2"},
+		{token: "()", want: "()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			if got := Mask(tt.token); got != tt.want {
+				t.Errorf("Mask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiner_Add(t *testing.T) {
+	miner := NewMiner(Options{})
+	messages := []string{
+		"user alice logged in from 10.0.0.1",
+		"request took 1200ms",
+		"user bob logged in from 10.0.0.2",
+		"connection refused",
+		"request took 15ms",
+		"user carol logged in from 192.168.1.4",
+	}
+	start := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	for i, message := range messages {
+		miner.Add(message, start.Add(time.Duration(i)*time.Minute), message)
+	}
+
+	want := []struct {
+		pattern string
+		count   int
+		first   time.Time
+		last    time.Time
+		example string
+	}{
+		{"user <*> logged in from <IP>", 3, start, start.Add(5 * time.Minute), messages[0]},
+		{"request took <NUM>", 2, start.Add(time.Minute), start.Add(4 * time.Minute), messages[1]},
+		{"connection refused", 1, start.Add(3 * time.Minute), start.Add(3 * time.Minute), messages[3]},
+	}
+
+	clusters := miner.Clusters()
+	if len(clusters) != len(want) {
+		t.Fatalf("Miner.Clusters() returned %d clusters, want %d", len(clusters), len(want))
+	}
+	for i, cluster := range clusters {
+		if cluster.Pattern() != want[i].pattern || cluster.Count != want[i].count {
+			t.Errorf("Miner.Clusters()[%d] = %q x%d, want %q x%d", i, cluster.Pattern(), cluster.Count, want[i].pattern, want[i].count)
+		}
+		if !cluster.First.Equal(want[i].first) || !cluster.Last.Equal(want[i].last) {
+			t.Errorf("Miner.Clusters()[%d] seen %v to %v, want %v to %v", i, cluster.First, cluster.Last, want[i].first, want[i].last)
+		}
+		if cluster.Example != want[i].example {
+			t.Errorf("Miner.Clusters()[%d] example = %q, want %q", i, cluster.Example, want[i].example)
+		}
+	}
+}
+
+func TestMiner_Similarity(t *testing.T) {
+	// Messages sharing too few tokens stay apart
+	miner := NewMiner(Options{Similarity: 0.8})
+	miner.Add("cache miss for key users", time.Time{}, "")
+	miner.Add("cache hit for key orders", time.Time{}, "")
+
+	if got := len(miner.Clusters()); got != 2 {
+		t.Errorf("Miner.Clusters() returned %d clusters, want 2", got)
+	}
+}
+
+func TestMiner_Seed_MaxClusters(t *testing.T) {
+	miner := NewMiner(Options{MaxClusters: 1})
+	seeded := miner.Seed("cache warmed", "cache warmed")
+	miner.Add("first message", time.Time{}, "")
+	miner.Add("second shape here", time.Time{}, "")
+	miner.Add("cache warmed", time.Time{}, "")
+
+	// Seeded clusters are kept beside MaxClusters mined ones
+	clusters := miner.Clusters()
+	if len(clusters) != 2 || miner.Evicted() != 1 {
+		t.Fatalf("Miner kept %d clusters and evicted %d, want 2 and 1", len(clusters), miner.Evicted())
+	}
+	if clusters[0] != seeded || seeded.Count != 1 || clusters[1].Pattern() != "second shape here" {
+		t.Errorf("Miner.Clusters() = %q, %q, want the seeded and the last mined cluster", clusters[0].Pattern(), clusters[1].Pattern())
+	}
+}
+
+func TestMiner_MaxClusters(t *testing.T) {
+	miner := NewMiner(Options{MaxClusters: 2})
+	miner.Add("first message", time.Time{}, "")
+	miner.Add("second shape here", time.Time{}, "")
+	miner.Add("first message", time.Time{}, "")
+	miner.Add("a third distinct shape", time.Time{}, "")
+
+	// The least recently seen cluster is dropped
+	clusters := miner.Clusters()
+	if len(clusters) != 2 || miner.Evicted() != 1 {
+		t.Fatalf("Miner kept %d clusters and evicted %d, want 2 and 1", len(clusters), miner.Evicted())
+	}
+	if clusters[0].Pattern() != "first message" || clusters[1].Pattern() != "a third distinct shape" {
+		t.Errorf("Miner.Clusters() = %q, %q, want the recently seen clusters", clusters[0].Pattern(), clusters[1].Pattern())
+	}
+
+	// Evicted clusters start over when seen again
+	miner.Add("second shape here", time.Time{}, "")
+	if miner.Evicted() != 2 {
+		t.Errorf("Miner.Evicted() = %d, want 2", miner.Evicted())
+	}
+}
+
+func TestMiner_Seed(t *testing.T) {
+	miner := NewMiner(Options{})
+	seeded := miner.Seed("request took <NUM>", "request took 12ms")
+
+	if got := miner.Add("request took 15ms", time.Time{}, ""); got != seeded {
+		t.Errorf("Miner.Add() joined cluster %d, want seeded cluster %d", got.ID, seeded.ID)
+	}
+	if seeded.Count != 1 || seeded.Example != "request took 12ms" {
+		t.Errorf("Miner.Seed() cluster = x%d %q, want x1 with the seeded example", seeded.Count, seeded.Example)
+	}
+
+	// Similar messages not following the pattern start their own cluster
+	seeded = miner.Seed("user <*> logged in", "user alice logged in")
+	if got := miner.Add("user bob logged out", time.Time{}, ""); got == seeded {
+		t.Errorf("Miner.Add() joined the seeded cluster %q", seeded.Pattern())
+	}
+	if seeded.Pattern() != "user <*> logged in" || seeded.Count != 0 {
+		t.Errorf("Miner.Seed() cluster = %q x%d, want it unchanged", seeded.Pattern(), seeded.Count)
+	}
+}
@@ -0,0 +1,156 @@
+package presets
+
+import (
+	"sort"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// Preset is a named built-in template for a common log format
+type Preset struct {
+	Name        string
+	Aliases     []string // Alternative names the preset can be selected by
+	Description string
+	Example     string // Sample log line in the preset's format
+	Literals    models.TemplateLiterals
+}
+
+// presets holds all built-in presets
+var presets = []Preset{
+	{
+		Name:        "nginx-combined",
+		Aliases:     []string{"nginx"},
+		Description: "nginx access log in the default combined format",
+		Example:     `203.0.113.7 - alice [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 2326 "https://example.com/" "Mozilla/5.0"`,
+		Literals: models.TemplateLiterals{
+			Source: `@remote-string@ - @user-string@ [@time-timestamp@] "@request-string@" @status-number@ @bytes-number@ "@referer-string@" "@agent-string@"`,
+			Target: `[@time@] @remote@ "@request@" @status@ @bytes@ "@agent@"`,
+		},
+	},
+	{
+		Name:        "nginx-common",
+		Description: "nginx access log in the common log format",
+		Example:     `203.0.113.7 - alice [10/Oct/2000:13:55:36 -0700] "GET /index.html HTTP/1.1" 200 2326`,
+		Literals: models.TemplateLiterals{
+			Source: `@remote-string@ - @user-string@ [@time-timestamp@] "@request-string@" @status-number@ @bytes-number@`,
+			Target: `[@time@] @remote@ "@request@" @status@ @bytes@`,
+		},
+	},
+	{
+		Name:        "apache-combined",
+		Description: "Apache access log in the combined log format",
+		Example:     `203.0.113.7 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"`,
+		Literals: models.TemplateLiterals{
+			Source: `@remote-string@ @ident-string@ @user-string@ [@time-timestamp@] "@request-string@" @status-number@ @bytes-string@ "@referer-string@" "@agent-string@"`,
+			Target: `[@time@] @remote@ "@request@" @status@ @bytes@ "@agent@"`,
+		},
+	},
+	{
+		Name:        "apache-common",
+		Description: "Apache access log in the common log format",
+		Example:     `203.0.113.7 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+		Literals: models.TemplateLiterals{
+			Source: `@remote-string@ @ident-string@ @user-string@ [@time-timestamp@] "@request-string@" @status-number@ @bytes-string@`,
+			Target: `[@time@] @remote@ "@request@" @status@ @bytes@`,
+		},
+	},
+	{
+		Name:        "syslog-rfc3164",
+		Aliases:     []string{"syslog"},
+		Description: "BSD syslog message (RFC 3164)",
+		Example:     `<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8`,
+		Literals: models.TemplateLiterals{
+			Source: `<@priority-number@>@month-string@ @day-number@ @clock-string@ @host-string@ @app-string@: @message-string@`,
+			Target: `@month@ @day@ @clock@ @host@ @app@: @message@`,
+		},
+	},
+	{
+		Name:        "syslog-rfc5424",
+		Description: "IETF syslog message (RFC 5424) without spaces in structured data",
+		Example:     `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 - An application event log entry`,
+		Literals: models.TemplateLiterals{
+			Source: `<@priority-number@>@version-number@ @time-timestamp@ @host-string@ @app-string@ @pid-string@ @msgid-string@ @data-string@ @message-string@`,
+			Target: `[@time@] @host@ @app@[@pid@]: @message@`,
+		},
+	},
+	{
+		Name:        "go-log",
+		Description: "Go standard library log package with default flags",
+		Example:     `2009/11/10 23:00:00 server started on :8080`,
+		Literals: models.TemplateLiterals{
+			Source: `@date-string@ @time-string@ @message-string@`,
+			Target: `[@date@ @time@] @message@`,
+		},
+	},
+	{
+		Name:        "klog",
+		Aliases:     []string{"glog"},
+		Description: "Kubernetes klog and glog header format",
+		Example:     `I0102 15:04:05.999999    1234 controller.go:123] Syncing deployment default/web`,
+		Literals: models.TemplateLiterals{
+			Source: `@severity-string{1}@@date-string{4}@ @time-string@ @thread-number@ @file-string@:@line-number@] @message-string@`,
+			Target: `[@severity@ @date@ @time@] @file@:@line@: @message@`,
+		},
+	},
+	{
+		Name:        "pm2",
+		Description: "Lines prefixed by pm2 with the process id and name",
+		Example:     `5|api  | Server listening on port 3000`,
+		Literals: models.TemplateLiterals{
+			Source: `@id-number@|@app-string@ | @message-string@`,
+			Target: `[@app@:@id@] @message@`,
+		},
+	},
+	{
+		Name:        "python-logging",
+		Description: "Python logging module default format",
+		Example:     `WARNING:root:disk usage above 90%`,
+		Literals: models.TemplateLiterals{
+			Source: `@level-string@:@logger-string@:@message-string@`,
+			Target: `[@level@] @logger@: @message@`,
+		},
+	},
+	{
+		Name:        "docker-json",
+		Description: "Docker json-file logging driver",
+		Example:     `{"log":"Server listening on port 3000\n","stream":"stdout","time":"2019-01-01T11:11:11.111111111Z"}`,
+		Literals: models.TemplateLiterals{
+			Source: `{"log":"@log-string@","stream":"@stream-string@","time":"@time-timestamp@"}`,
+			Target: `[@time@] @stream@: @log@`,
+		},
+	},
+	{
+		Name:        "cri",
+		Description: "Kubernetes CRI container log format",
+		Example:     `2016-10-06T00:17:09.669794202Z stdout F Server listening on port 3000`,
+		Literals: models.TemplateLiterals{
+			Source: `@time-timestamp@ @stream-string@ @tag-string@ @log-string@`,
+			Target: `[@time@] @stream@: @log@`,
+		},
+	},
+}
+
+// Get returns the preset with the given name or alias
+func Get(name string) (Preset, bool) {
+	for _, preset := range presets {
+		if preset.Name == name {
+			return preset, true
+		}
+		for _, alias := range preset.Aliases {
+			if alias == name {
+				return preset, true
+			}
+		}
+	}
+	return Preset{}, false
+}
+
+// List returns all presets sorted by name
+func List() []Preset {
+	list := make([]Preset, len(presets))
+	copy(list, presets)
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
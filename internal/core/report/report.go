@@ -0,0 +1,177 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// DefaultMaxLines is the default number of offending line numbers kept per problem
+const DefaultMaxLines = 10
+
+// Report summarizes a processing run
+type Report struct {
+	Template       string        `json:"template"`       // Template the lines were parsed with
+	Lines          int           `json:"lines"`          // Lines read from the input
+	Records        int           `json:"records"`        // Records written to the output
+	Matched        int           `json:"matched"`        // Records matching the template
+	Unmatched      int           `json:"unmatched"`      // Records not matching the template
+	UnmatchedLines []int         `json:"unmatchedLines"` // First line numbers of unmatched records
+	Fields         []FieldReport `json:"fields"`         // Fields with values that couldn't be coerced into their type
+	Elapsed        float64       `json:"elapsedSeconds"`
+	LinesPerSecond float64       `json:"linesPerSecond"`
+}
+
+// FieldReport counts the values of a field that couldn't be coerced into its
+// type and were output as is
+type FieldReport struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Failures int    `json:"failures"`
+	Lines    []int  `json:"lines"`   // First line numbers of failing values
+	Example  string `json:"example"` // First failing value
+}
+
+// Collector gathers the statistics of a run as records are processed
+type Collector struct {
+	report   Report
+	maxLines int
+	start    time.Time
+	fields   map[string]*FieldReport
+}
+
+// NewCollector creates a collector for a run starting now. At most maxLines
+// offending line numbers are kept per problem.
+func NewCollector(template string, maxLines int) *Collector {
+	return &Collector{
+		report:   Report{Template: template, UnmatchedLines: []int{}, Fields: []FieldReport{}},
+		maxLines: maxLines,
+		start:    time.Now(),
+		fields:   make(map[string]*FieldReport),
+	}
+}
+
+// Add counts a parsed record and checks its field values against their types
+func (c *Collector) Add(record *models.Record) {
+	if !record.Matched {
+		c.report.Unmatched++
+		c.report.UnmatchedLines = c.appendLine(c.report.UnmatchedLines, record.Line)
+		return
+	}
+	c.report.Matched++
+
+	for _, name := range record.Names() {
+		field, _ := record.Field(name)
+		value, _ := record.Value(name)
+		if _, err := field.Coerce(value); err == nil {
+			continue
+		}
+
+		fieldReport, ok := c.fields[name]
+		if !ok {
+			fieldReport = &FieldReport{Name: name, Type: field.TypeName(), Lines: []int{}, Example: value}
+			c.fields[name] = fieldReport
+		}
+		fieldReport.Failures++
+		fieldReport.Lines = c.appendLine(fieldReport.Lines, record.Line)
+	}
+}
+
+// Finish completes the report with the number of lines read and records written
+func (c *Collector) Finish(lines, records int) *Report {
+	report := c.report
+	report.Lines = lines
+	report.Records = records
+
+	report.Fields = []FieldReport{}
+	for _, fieldReport := range c.fields {
+		report.Fields = append(report.Fields, *fieldReport)
+	}
+	sort.Slice(report.Fields, func(i, j int) bool {
+		if report.Fields[i].Failures != report.Fields[j].Failures {
+			return report.Fields[i].Failures > report.Fields[j].Failures
+		}
+		return report.Fields[i].Name < report.Fields[j].Name
+	})
+
+	report.Elapsed = time.Since(c.start).Seconds()
+	if report.Elapsed > 0 {
+		report.LinesPerSecond = float64(lines) / report.Elapsed
+	}
+	return &report
+}
+
+// appendLine adds a line number to a list unless it holds maxLines already
+func (c *Collector) appendLine(lines []int, line int) []int {
+	if len(lines) >= c.maxLines {
+		return lines
+	}
+	return append(lines, line)
+}
+
+// MatchRate returns the percentage of records matching the template
+func (r *Report) MatchRate() float64 {
+	if r.Matched+r.Unmatched == 0 {
+		return 0
+	}
+	return 100 * float64(r.Matched) / float64(r.Matched+r.Unmatched)
+}
+
+// WriteText writes the report in a human readable form
+func (r *Report) WriteText(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Template:   %s\nLines:      %d\nRecords:    %d\nMatched:    %d (%.1f%%)\nUnmatched:  %d%s\nElapsed:    %.3fs (%.0f lines/s)\n",
+		r.Template, r.Lines, r.Records, r.Matched, r.MatchRate(), r.Unmatched, lineList(r.UnmatchedLines, r.Unmatched),
+		r.Elapsed, r.LinesPerSecond)
+	if err != nil {
+		return err
+	}
+
+	if len(r.Fields) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "Values output as is:"); err != nil {
+		return err
+	}
+	for _, field := range r.Fields {
+		_, err := fmt.Fprintf(w, "  %s (%s): %d%s, e.g. %q\n", field.Name, field.Type, field.Failures, lineList(field.Lines, field.Failures), field.Example)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the report as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// lineList describes the first line numbers of a count of problems, like
+// " (lines 3, 8, ...)"
+func lineList(lines []int, count int) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	list := " (line"
+	if len(lines) > 1 {
+		list += "s"
+	}
+	for i, line := range lines {
+		if i > 0 {
+			list += ","
+		}
+		list += fmt.Sprintf(" %d", line)
+	}
+	if count > len(lines) {
+		list += ", ..."
+	}
+	return list + ")"
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gitKashish/golog/internal/core/models"
+)
+
+// newRecord creates a record of a level and a count field, unmatched if level is empty
+func newRecord(line int, level, count string) *models.Record {
+	record := models.NewRecord(line, level+" "+count)
+	if level == "" {
+		return record
+	}
+	record.Set(&models.Field{Name: "level", Type: models.String}, level)
+	record.Set(&models.Field{Name: "count", Type: models.Number}, count)
+	record.Matched = true
+	return record
+}
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector("template.yaml", 2)
+	records := []*models.Record{
+		newRecord(1, "INFO", "1"),
+		newRecord(2, "", ""),
+		newRecord(3, "WARN", "many"),
+		newRecord(4, "", ""),
+		newRecord(5, "INFO", "n/a"),
+		newRecord(6, "", ""),
+		newRecord(7, "INFO", "none"),
+	}
+	for _, record := range records {
+		collector.Add(record)
+	}
+
+	report := collector.Finish(8, 7)
+	if report.Lines != 8 || report.Records != 7 || report.Matched != 4 || report.Unmatched != 3 {
+		t.Errorf("Finish() counts = %d lines, %d records, %d matched, %d unmatched",
+			report.Lines, report.Records, report.Matched, report.Unmatched)
+	}
+	if !reflect.DeepEqual(report.UnmatchedLines, []int{2, 4}) {
+		t.Errorf("Finish() unmatched lines = %v, want [2 4]", report.UnmatchedLines)
+	}
+
+	want := []FieldReport{{Name: "count", Type: "number", Failures: 3, Lines: []int{3, 5}, Example: "many"}}
+	if !reflect.DeepEqual(report.Fields, want) {
+		t.Errorf("Finish() fields = %+v, want %+v", report.Fields, want)
+	}
+	if rate := report.MatchRate(); rate < 57 || rate > 58 {
+		t.Errorf("MatchRate() = %f, want 57.1", rate)
+	}
+
+	out := strings.Builder{}
+	if err := report.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	for _, part := range []string{"Unmatched:  3 (lines 2, 4, ...)", `count (number): 3 (lines 3, 5, ...), e.g. "many"`} {
+		if !strings.Contains(out.String(), part) {
+			t.Errorf("WriteText() = %q, want it to contain %q", out.String(), part)
+		}
+	}
+}
+
+func TestCollector_Timestamps(t *testing.T) {
+	collector := NewCollector("template.yaml", DefaultMaxLines)
+	for i, value := range []string{"2024-01-02T03:04:05Z", "02/Jan/2024:03:04:05 +0000", "Tue, 02 Jan 2024 03:04:05 +0000", "yesterday"} {
+		record := models.NewRecord(i+1, value)
+		record.Set(&models.Field{Name: "ts", Type: models.Timestamp}, value)
+		record.Matched = true
+		collector.Add(record)
+	}
+
+	// Only the value in no recognized layout is a failure
+	want := []FieldReport{{Name: "ts", Type: "timestamp", Failures: 1, Lines: []int{4}, Example: "yesterday"}}
+	if report := collector.Finish(4, 4); !reflect.DeepEqual(report.Fields, want) {
+		t.Errorf("Finish() fields = %+v, want %+v", report.Fields, want)
+	}
+}
@@ -0,0 +1,246 @@
+package stacktrace
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Languages of recognized stack traces
+const (
+	Go     = "go"
+	JVM    = "jvm"
+	Node   = "node"
+	Python = "python"
+)
+
+// Indentation of frames and of their continuation lines when rendered
+const (
+	frameIndent = "    "
+	innerIndent = "        "
+)
+
+// DefaultVendorPaths are the paths of frames outside of the application, like
+// dependencies and standard libraries, folded when rendering a trace
+var DefaultVendorPaths = []string{
+	"node_modules/",
+	"node:internal/",
+	"/usr/local/go/src/",
+	"/go/pkg/mod/",
+	"site-packages/",
+	"dist-packages/",
+	"java.base/",
+}
+
+var (
+	// at com.example.Service.call(Service.java:42) or at java.base/java.lang.Thread.run(Thread.java:833)
+	jvmFrameRegex = regexp.MustCompile(`^at\s+([^\s(]+)\(([^)]*)\)$`)
+	// at handler (/app/index.js:10:5), at async Promise.all (index 0) or at /app/index.js:10:5
+	nodeFrameRegex = regexp.MustCompile(`^at\s+(?:async\s+)?(?:(.+?)\s+\((.+)\)|(\S+:\d+:\d+))$`)
+	// File "/app/main.py", line 10, in handler
+	pythonFrameRegex = regexp.MustCompile(`^File "([^"]+)", line (\d+), in (.+)$`)
+	// /app/main.go:12 +0x1d, following the function of a Go frame
+	goLocationRegex = regexp.MustCompile(`^\s+(\S+\.go:\d+)(?:\s+\+0x[0-9a-f]+)?$`)
+	// Prefix and suffix of the function of a Go frame, like main.(*Server).handle(0xc000010000, 0x1)
+	// or created by main.main in goroutine 1
+	goCallRegex = regexp.MustCompile(`^created by |\([^()]*\)$| in goroutine \d+$`)
+)
+
+// Frame is a call of a stack trace
+type Frame struct {
+	Function string   // Called function, empty for anonymous JavaScript functions
+	Location string   // File and line of the call, like "/app/main.go:12" or "Service.java:42"
+	Lines    []string // Lines of the frame without their indentation, continuation lines keep the indentation relative to the first of them
+}
+
+// String describes a frame on a line, like "main.handle (/app/main.go:12)"
+func (f Frame) String() string {
+	switch {
+	case f.Function == "":
+		return f.Location
+	case f.Location == "":
+		return f.Function
+	}
+	return f.Function + " (" + f.Location + ")"
+}
+
+// IsVendor reports whether a frame is outside of the application, found in one
+// of a list of vendor paths
+func (f Frame) IsVendor(vendorPaths []string) bool {
+	text := strings.Join(f.Lines, "\n")
+	for _, path := range vendorPaths {
+		if path != "" && strings.Contains(text, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// entry is a line of a stack trace that is not a frame, or a frame
+type entry struct {
+	text  string
+	frame *Frame
+}
+
+// Trace is a parsed stack trace
+type Trace struct {
+	Language string
+	entries  []entry
+}
+
+// Frames returns the frames of the trace, innermost first
+func (t *Trace) Frames() []Frame {
+	frames := []Frame{}
+	for _, entry := range t.entries {
+		if entry.frame != nil {
+			frames = append(frames, *entry.frame)
+		}
+	}
+
+	// Python prints the innermost frame last
+	if t.Language == Python {
+		for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+			frames[i], frames[j] = frames[j], frames[i]
+		}
+	}
+	return frames
+}
+
+// Top returns the innermost frame of the application, leaving out frames found
+// in vendor paths. It is false if all frames are vendor frames.
+func (t *Trace) Top(vendorPaths []string) (Frame, bool) {
+	for _, frame := range t.Frames() {
+		if !frame.IsVendor(vendorPaths) {
+			return frame, true
+		}
+	}
+	return Frame{}, false
+}
+
+// Parse parses a Go panic, a JVM exception, a Node.js error or a Python
+// traceback. It is false if the text holds no recognized frames.
+func Parse(text string) (*Trace, bool) {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimRight(text, "\r\n"), "\r\n", "\n"), "\n")
+	trace := &Trace{Language: language(lines)}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		var frame *Frame
+		switch trace.Language {
+		case Go:
+			// The function of a frame is followed by its location on an indented line
+			if i+1 < len(lines) && trimmed != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+				if location := goLocationRegex.FindStringSubmatch(lines[i+1]); location != nil {
+					function := goCallRegex.ReplaceAllString(trimmed, "")
+					frame = &Frame{Function: function, Location: location[1], Lines: []string{trimmed, strings.TrimSpace(lines[i+1])}}
+					i++
+				}
+			}
+		case JVM:
+			if match := jvmFrameRegex.FindStringSubmatch(trimmed); match != nil {
+				frame = &Frame{Function: match[1], Location: match[2], Lines: []string{trimmed}}
+			}
+		case Node:
+			if match := nodeFrameRegex.FindStringSubmatch(trimmed); match != nil {
+				frame = &Frame{Function: match[1], Location: match[2] + match[3], Lines: []string{trimmed}}
+			}
+		case Python:
+			if match := pythonFrameRegex.FindStringSubmatch(trimmed); match != nil {
+				frame = &Frame{Function: match[3], Location: match[1] + ":" + match[2], Lines: []string{trimmed}}
+				// The source line and carets of a frame are indented deeper than the
+				// frame, carets keep their position relative to the source line
+				depth, base := indentation(line), -1
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && indentation(lines[i+1]) > depth {
+					next := strings.TrimRight(lines[i+1], " \t")
+					if base < 0 {
+						base = indentation(next)
+					}
+					frame.Lines = append(frame.Lines, next[min(base, indentation(next)):])
+					i++
+				}
+			}
+		}
+
+		if frame != nil {
+			trace.entries = append(trace.entries, entry{frame: frame})
+		} else {
+			trace.entries = append(trace.entries, entry{text: trimmed})
+		}
+	}
+
+	if trace.Language == "" || len(trace.Frames()) == 0 {
+		return nil, false
+	}
+	return trace, true
+}
+
+// language recognizes the language of a stack trace by its frames
+func language(lines []string) string {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case pythonFrameRegex.MatchString(trimmed):
+			return Python
+		case jvmFrameRegex.MatchString(trimmed):
+			return JVM
+		case nodeFrameRegex.MatchString(trimmed):
+			return Node
+		case i+1 < len(lines) && trimmed != "" && goLocationRegex.MatchString(lines[i+1]):
+			return Go
+		}
+	}
+	return ""
+}
+
+// indentation returns the width of the leading whitespace of a line
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// Render writes the trace with consistent indentation: messages unindented,
+// frames indented and their continuation lines, like the location of a Go
+// frame, indented twice. Runs of frames found in vendor paths are folded into
+// a line like "… 12 frames hidden".
+func (t *Trace) Render(vendorPaths []string) string {
+	lines := []string{}
+	hidden := 0
+	fold := func() {
+		if hidden > 0 {
+			lines = append(lines, frameIndent+hiddenFrames(hidden))
+			hidden = 0
+		}
+	}
+
+	for _, entry := range t.entries {
+		if entry.frame != nil && entry.frame.IsVendor(vendorPaths) {
+			hidden++
+			continue
+		}
+		fold()
+
+		switch {
+		case entry.frame != nil:
+			lines = append(lines, frameIndent+entry.frame.Lines[0])
+			for _, line := range entry.frame.Lines[1:] {
+				lines = append(lines, innerIndent+line)
+			}
+		case strings.HasPrefix(entry.text, "..."):
+			// Frames in common with the enclosing trace, like "... 12 more"
+			lines = append(lines, frameIndent+entry.text)
+		default:
+			lines = append(lines, entry.text)
+		}
+	}
+	fold()
+	return strings.Join(lines, "\n")
+}
+
+// hiddenFrames describes a number of folded frames
+func hiddenFrames(count int) string {
+	if count == 1 {
+		return "… 1 frame hidden"
+	}
+	return fmt.Sprintf("… %d frames hidden", count)
+}
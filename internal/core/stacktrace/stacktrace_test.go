@@ -0,0 +1,154 @@
+package stacktrace
+
+import (
+	"strings"
+	"testing"
+)
+
+const goTrace = `panic: boom
+
+goroutine 1 [running]:
+main.(*Server).handle(0xc000010000, 0x1)
+	/app/server.go:42 +0x1d
+net/http.HandlerFunc.ServeHTTP(0x0)
+	/usr/local/go/src/net/http/server.go:2166 +0x29
+net/http.serverHandler.ServeHTTP(0x0)
+	/usr/local/go/src/net/http/server.go:3137 +0x8e
+main.main()
+	/app/main.go:10 +0x25`
+
+const jvmTrace = `java.lang.IllegalStateException: bad state
+	at com.example.Service.call(Service.java:42)
+	at java.base/java.lang.Thread.run(Thread.java:833)
+Caused by: java.io.IOException: closed
+	at com.example.Io.read(Io.java:7)
+	... 1 more`
+
+const nodeTrace = `TypeError: x is undefined
+    at handler (/app/index.js:10:5)
+    at Layer.handle (/app/node_modules/express/lib/router/layer.js:95:5)
+    at next (/app/node_modules/express/lib/router/route.js:137:13)
+    at /app/index.js:20:3`
+
+const pythonTrace = `Traceback (most recent call last):
+  File "/usr/lib/python3/site-packages/flask/app.py", line 10, in wsgi_app
+    response = self.full_dispatch_request()
+  File "/app/main.py", line 5, in handler
+    return 1 / 0
+           ~~^~~
+ZeroDivisionError: division by zero`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantLanguage string
+		wantFrames   int
+		wantTop      string
+	}{
+		{name: "Go", text: goTrace, wantLanguage: Go, wantFrames: 4, wantTop: "main.(*Server).handle (/app/server.go:42)"},
+		{name: "JVM", text: jvmTrace, wantLanguage: JVM, wantFrames: 3, wantTop: "com.example.Service.call (Service.java:42)"},
+		{name: "Node", text: nodeTrace, wantLanguage: Node, wantFrames: 4, wantTop: "handler (/app/index.js:10:5)"},
+		{name: "Python", text: pythonTrace, wantLanguage: Python, wantFrames: 2, wantTop: "handler (/app/main.py:5)"},
+		{name: "CRLF", text: strings.ReplaceAll(nodeTrace, "\n", "\r\n"), wantLanguage: Node, wantFrames: 4, wantTop: "handler (/app/index.js:10:5)"},
+		{
+			name:         "Go goroutine creation",
+			text:         "panic: boom\n\ngoroutine 7 [running]:\nnet/http.(*conn).serve(0xc0001)\n\t/usr/local/go/src/net/http/server.go:2039 +0x1\ncreated by main.start in goroutine 1\n\t/app/main.go:30 +0x5",
+			wantLanguage: Go,
+			wantFrames:   2,
+			wantTop:      "main.start (/app/main.go:30)",
+		},
+		{name: "Vendor frames only", text: "Error: x\n    at node:internal/process/task_queues:95:5", wantLanguage: Node, wantFrames: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace, ok := Parse(tt.text)
+			if !ok {
+				t.Fatalf("Parse() did not recognize the trace")
+			}
+			if trace.Language != tt.wantLanguage {
+				t.Errorf("Parse() language = %q, want %q", trace.Language, tt.wantLanguage)
+			}
+			if frames := trace.Frames(); len(frames) != tt.wantFrames {
+				t.Errorf("Parse() frames = %+v, want %d frames", frames, tt.wantFrames)
+			}
+			// Without application frames the top frame is empty
+			if top, _ := trace.Top(DefaultVendorPaths); top.String() != tt.wantTop {
+				t.Errorf("Trace.Top() = %q, want %q", top, tt.wantTop)
+			}
+		})
+	}
+}
+
+func TestParse_NotATrace(t *testing.T) {
+	for _, text := range []string{"", "connection refused", "panic: boom", "at the end of the day"} {
+		if _, ok := Parse(text); ok {
+			t.Errorf("Parse(%q) recognized a trace", text)
+		}
+	}
+}
+
+func TestTrace_Render(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		vendorPaths []string
+		want        string
+	}{
+		{
+			name: "Go",
+			text: goTrace,
+			want: "panic: boom\n\ngoroutine 1 [running]:\n" +
+				"    main.(*Server).handle(0xc000010000, 0x1)\n        /app/server.go:42 +0x1d\n" +
+				"    … 2 frames hidden\n" +
+				"    main.main()\n        /app/main.go:10 +0x25",
+		},
+		{
+			name: "JVM",
+			text: jvmTrace,
+			want: "java.lang.IllegalStateException: bad state\n" +
+				"    at com.example.Service.call(Service.java:42)\n" +
+				"    … 1 frame hidden\n" +
+				"Caused by: java.io.IOException: closed\n" +
+				"    at com.example.Io.read(Io.java:7)\n" +
+				"    ... 1 more",
+		},
+		{
+			name: "Python keeps carets under the source line",
+			text: pythonTrace,
+			want: "Traceback (most recent call last):\n" +
+				"    … 1 frame hidden\n" +
+				"    File \"/app/main.py\", line 5, in handler\n" +
+				"        return 1 / 0\n" +
+				"               ~~^~~\n" +
+				"ZeroDivisionError: division by zero",
+		},
+		{
+			name:        "Without vendor paths",
+			text:        nodeTrace,
+			vendorPaths: []string{},
+			want: "TypeError: x is undefined\n" +
+				"    at handler (/app/index.js:10:5)\n" +
+				"    at Layer.handle (/app/node_modules/express/lib/router/layer.js:95:5)\n" +
+				"    at next (/app/node_modules/express/lib/router/route.js:137:13)\n" +
+				"    at /app/index.js:20:3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trace, ok := Parse(tt.text)
+			if !ok {
+				t.Fatalf("Parse() did not recognize the trace")
+			}
+			vendorPaths := tt.vendorPaths
+			if vendorPaths == nil {
+				vendorPaths = DefaultVendorPaths
+			}
+			if got := trace.Render(vendorPaths); got != tt.want {
+				t.Errorf("Trace.Render() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -20,6 +21,7 @@ func NewAPIHandler() *http.ServeMux {
 	// Register Routes
 	router.HandleFunc("GET /greet", handleGreet)
 	router.HandleFunc("POST /format", handleFormat)
+	router.HandleFunc("POST /explain", handleExplain)
 
 	return router
 }
@@ -61,3 +63,26 @@ func handleFormat(w http.ResponseWriter, r *http.Request) {
 
 	tmpl.ExecuteTemplate(w, "form.log.pretty", data)
 }
+
+// handleExplain describes how a single log line is matched against a template
+func handleExplain(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err.Error())
+		logger.Error("Error parsing form: %v", err)
+		return
+	}
+
+	p := parser.NewTemplateParser()
+	err = p.SetTemplate(r.FormValue("source_template"), r.FormValue("target_template"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err.Error())
+		logger.Error("Error setting template: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p.Explain(r.FormValue("raw_log")))
+}
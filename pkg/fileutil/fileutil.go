@@ -3,7 +3,9 @@ package fileutil
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -11,6 +13,12 @@ import (
 type FileReader interface {
 	// ReadLines reads all lines from a file
 	ReadLines(filepath string) ([]string, error)
+	// EachLine calls fn with each line of a file
+	EachLine(filepath string, fn func(line string) error) error
+	// SplitChunks splits a file into byte ranges starting at line boundaries
+	SplitChunks(filepath string, count int) ([]Chunk, error)
+	// EachLineInChunk calls fn with each line of a byte range of a file
+	EachLineInChunk(filepath string, chunk Chunk, fn func(line string) error) error
 	// FileExists checks if a file exists
 	FileExists(filepath string) bool
 }
@@ -22,15 +30,56 @@ type FileWriter interface {
 }
 
 // FileUtil implements both FileReader and FileWriter interfaces
-type FileUtil struct{}
+type FileUtil struct {
+	Options ReadOptions // How lines are read
+}
 
-// NewFileUtil creates a new FileUtil
+// NewFileUtil creates a new FileUtil reading lines with the default options
 func NewFileUtil() *FileUtil {
 	return &FileUtil{}
 }
 
 // ReadLines reads all lines from a file
 func (f *FileUtil) ReadLines(filepath string) ([]string, error) {
+	lines := []string{}
+	err := f.EachLine(filepath, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// EachLine calls fn with each line of a file without holding the whole file in memory,
+// decoded into UTF-8 according to the read options. Reading stops at the first error
+// returned by fn, or at a line that can't be read.
+func (f *FileUtil) EachLine(filepath string, fn func(line string) error) error {
+	if !f.FileExists(filepath) {
+		return fmt.Errorf("file does not exist: %s", filepath)
+	}
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	return eachLine(newLineReader(file, f.Options, true), fn)
+}
+
+// Chunk is a byte range of a file, from Start up to but not including End
+type Chunk struct {
+	Start int64
+	End   int64
+}
+
+// SplitChunks splits a file into at most count byte ranges of about the same
+// size. Each range but the last ends right after a line break, so that every
+// line lies within a single range.
+func (f *FileUtil) SplitChunks(filepath string, count int) ([]Chunk, error) {
 	if !f.FileExists(filepath) {
 		return nil, fmt.Errorf("file does not exist: %s", filepath)
 	}
@@ -41,18 +90,81 @@ func (f *FileUtil) ReadLines(filepath string) ([]string, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	if err = scanner.Err(); err != nil {
+	info, err := file.Stat()
+	if err != nil {
 		return nil, fmt.Errorf("error reading file: %w", err)
 	}
+	size := info.Size()
+	count = max(count, 1)
 
-	lines := []string{}
-	for scanner.Scan() {
-		line := scanner.Text()
-		lines = append(lines, line)
+	// Line breaks can't be found without decoding UTF-16
+	encoding := skipByteOrderMark(bufio.NewReader(file), f.Options.Encoding)
+	if encoding == EncodingUTF16LE || encoding == EncodingUTF16BE {
+		count = 1
 	}
 
-	return lines, nil
+	chunks := []Chunk{}
+	start := int64(0)
+	for i := 1; i < count; i++ {
+		// Move the boundary forward to the start of the next line
+		end, err := nextLine(file, max(size*int64(i)/int64(count), start), size)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		if end >= size {
+			break
+		}
+		if end > start {
+			chunks = append(chunks, Chunk{Start: start, End: end})
+			start = end
+		}
+	}
+	return append(chunks, Chunk{Start: start, End: size}), nil
+}
+
+// nextLine returns the offset of the first line starting at or after offset,
+// or the size of the file if there is none
+func nextLine(file *os.File, offset, size int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+
+	// The line starts at offset if the byte before it is a line break
+	reader := bufio.NewReader(io.NewSectionReader(file, offset-1, size-offset+1))
+	line, err := reader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	return offset - 1 + int64(len(line)), nil
+}
+
+// EachLineInChunk calls fn with each line of a byte range of a file, as
+// returned by SplitChunks. Reading stops at the first error returned by fn.
+func (f *FileUtil) EachLineInChunk(filepath string, chunk Chunk, fn func(line string) error) error {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	section := io.NewSectionReader(file, chunk.Start, chunk.End-chunk.Start)
+	return eachLine(newLineReader(section, f.Options, chunk.Start == 0), fn)
+}
+
+// eachLine calls fn with each line read by reader
+func eachLine(reader *lineReader, fn func(line string) error) error {
+	for {
+		line, err := reader.next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
 }
 
 // FileExists checks if a file exists and is not a directory
@@ -73,11 +185,53 @@ func (f *FileUtil) WriteLines(lines []string, filepath string) error {
 	defer file.Close()
 
 	for _, line := range lines {
-		entry := line + strings.Repeat("-", 80) + "\n"
-		_, err := file.WriteString(entry)
+		_, err := file.WriteString(Entry(line))
 		if err != nil {
 			return fmt.Errorf("error writing to file: %w", err)
 		}
 	}
 	return nil
 }
+
+// Entry returns a line as it is written to output files, followed by a separator
+func Entry(line string) string {
+	return line + strings.Repeat("-", 80) + "\n"
+}
+
+// sizeUnits maps size suffixes to their multiplier in bytes
+var sizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1000},
+	{"MB", 1000 * 1000},
+	{"GB", 1000 * 1000 * 1000},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"B", 1},
+}
+
+// ParseSize parses a human readable size like "512", "10MB" or "1GiB" into bytes.
+// KB, MB and GB are decimal units while KiB, MiB and GiB are binary, and the
+// short forms K, M and G are binary too, like the bytes field type.
+func ParseSize(size string) (int64, error) {
+	value := strings.TrimSpace(size)
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(strings.ToUpper(value), strings.ToUpper(unit.suffix)) {
+			value = strings.TrimSpace(value[:len(value)-len(unit.suffix)])
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size `%s`", size)
+	}
+	return number * multiplier, nil
+}
@@ -1,6 +1,7 @@
 package fileutil
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -100,6 +101,96 @@ func TestFileUtil_ReadLines(t *testing.T) {
 	}
 }
 
+func TestFileUtil_EachLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	if err := os.WriteFile(path, []byte("Line 1\nLine 2\nLine 3\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	fileUtil := NewFileUtil()
+
+	// Test stopping at the first error of the callback
+	stop := errors.New("stop")
+	lines := []string{}
+	err := fileUtil.EachLine(path, func(line string) error {
+		lines = append(lines, line)
+		if len(lines) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("FileUtil.EachLine() error = %v, want %v", err, stop)
+	}
+	if strings.Join(lines, ",") != "Line 1,Line 2" {
+		t.Errorf("FileUtil.EachLine() read %v, want first two lines", lines)
+	}
+}
+
+func TestFileUtil_SplitChunks(t *testing.T) {
+	fileUtil := NewFileUtil()
+
+	tests := []struct {
+		name    string
+		content string
+		count   int
+		want    int // Number of chunks
+	}{
+		{"empty file", "", 4, 1},
+		{"single line", "only line", 4, 1},
+		{"more chunks than lines", "a\nb\nc\n", 10, 3},
+		{"long line", "a\n" + strings.Repeat("x", 100) + "\nb\n", 4, 2},
+		{"many lines", strings.Repeat("line\n", 100), 4, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lines.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			chunks, err := fileUtil.SplitChunks(path, tt.count)
+			if err != nil {
+				t.Fatalf("FileUtil.SplitChunks() error = %v", err)
+			}
+			if len(chunks) != tt.want {
+				t.Errorf("FileUtil.SplitChunks() = %v, want %d chunks", chunks, tt.want)
+			}
+
+			// Chunks cover the file and hold its lines in order
+			lines := []string{}
+			end := int64(0)
+			for _, chunk := range chunks {
+				if chunk.Start != end {
+					t.Fatalf("FileUtil.SplitChunks() = %v, chunk starts at %d, want %d", chunks, chunk.Start, end)
+				}
+				if chunk.Start > 0 && tt.content[chunk.Start-1] != '\n' {
+					t.Errorf("FileUtil.SplitChunks() = %v, chunk doesn't start a line", chunks)
+				}
+				end = chunk.End
+				err := fileUtil.EachLineInChunk(path, chunk, func(line string) error {
+					lines = append(lines, line)
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("FileUtil.EachLineInChunk() error = %v", err)
+				}
+			}
+			if end != int64(len(tt.content)) {
+				t.Errorf("FileUtil.SplitChunks() = %v, want chunks up to %d", chunks, len(tt.content))
+			}
+			want, _ := fileUtil.ReadLines(path)
+			if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+				t.Errorf("FileUtil.EachLineInChunk() read %q, want %q", lines, want)
+			}
+		})
+	}
+
+	if _, err := fileUtil.SplitChunks(filepath.Join(t.TempDir(), "missing.txt"), 2); err == nil {
+		t.Errorf("FileUtil.SplitChunks() error = nil, want error for non-existing file")
+	}
+}
+
 func TestFileUtil_WriteLines(t *testing.T) {
 	// Create a temporary file path
 	tmpFile, err := os.CreateTemp("", "fileutil_test_*.txt")
@@ -133,3 +224,38 @@ func TestFileUtil_WriteLines(t *testing.T) {
 		t.Errorf("FileUtil.WriteLines() wrote %v, want %v", string(content), expectedContent)
 	}
 }
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "512", want: 512},
+		{size: "10KB", want: 10000},
+		{size: "10kb", want: 10000},
+		{size: "10K", want: 10 << 10},
+		{size: "10KiB", want: 10 << 10},
+		{size: "4MB", want: 4000000},
+		{size: "4M", want: 4 << 20},
+		{size: "1MiB", want: 1 << 20},
+		{size: "2GB", want: 2000000000},
+		{size: "2G", want: 2 << 30},
+		{size: "", wantErr: true},
+		{size: "ten", wantErr: true},
+		{size: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := ParseSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
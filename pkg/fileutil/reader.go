@@ -0,0 +1,258 @@
+package fileutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Policies for lines longer than the maximum line size
+const (
+	LongLinesError    = "error"    // Stop reading with an error
+	LongLinesTruncate = "truncate" // Keep the start of the line and skip the rest
+)
+
+// Encodings of input files
+const (
+	EncodingAuto    = "auto" // Detected from the byte order mark, UTF-8 if there is none
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingLatin1  = "latin1"
+)
+
+// DefaultMaxLineSize is the default size in bytes of the longest line read
+const DefaultMaxLineSize = 1 << 20
+
+// ErrLineTooLong is returned for lines longer than the maximum line size
+var ErrLineTooLong = errors.New("line too long")
+
+// ReadOptions controls how lines are read from files. The zero value reads
+// lines of up to DefaultMaxLineSize, fails on longer ones and detects the
+// encoding.
+type ReadOptions struct {
+	MaxLineSize int64  // Size in bytes of the longest line, DefaultMaxLineSize if 0
+	LongLines   string // LongLinesError or LongLinesTruncate
+	Encoding    string // One of the Encoding constants, EncodingAuto if empty
+}
+
+// byteOrderMarks maps the encodings detected from a byte order mark to the mark
+var byteOrderMarks = []struct {
+	encoding string
+	mark     []byte
+}{
+	{EncodingUTF8, []byte{0xEF, 0xBB, 0xBF}},
+	{EncodingUTF16LE, []byte{0xFF, 0xFE}},
+	{EncodingUTF16BE, []byte{0xFE, 0xFF}},
+}
+
+// LineError reports a line that couldn't be read
+type LineError struct {
+	Line int // Number of the line, starting at 1
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// IsEncoding reports whether name is a supported encoding
+func IsEncoding(name string) bool {
+	switch name {
+	case EncodingAuto, EncodingUTF8, EncodingUTF16LE, EncodingUTF16BE, EncodingLatin1:
+		return true
+	}
+	return false
+}
+
+// IsLongLinesPolicy reports whether name is a policy for long lines
+func IsLongLinesPolicy(name string) bool {
+	return name == LongLinesError || name == LongLinesTruncate
+}
+
+// lineReader reads lines from a file decoded into UTF-8. Line breaks may be
+// LF or CRLF and are not part of the lines.
+type lineReader struct {
+	reader   *bufio.Reader
+	options  ReadOptions
+	encoding string // Encoding of the lines once UTF-16 input is converted to UTF-8
+	line     int    // Number of the last line read
+}
+
+// newLineReader creates a line reader. The encoding is detected from a byte
+// order mark if start is true, as r is then at the start of the file.
+func newLineReader(r io.Reader, options ReadOptions, start bool) *lineReader {
+	if options.MaxLineSize <= 0 {
+		options.MaxLineSize = DefaultMaxLineSize
+	}
+
+	reader := bufio.NewReader(r)
+	encoding := options.Encoding
+	if start {
+		encoding = skipByteOrderMark(reader, encoding)
+	}
+	if encoding == EncodingAuto || encoding == "" {
+		encoding = EncodingUTF8
+	}
+
+	// UTF-16 is converted before splitting lines, as its code units may hold line break bytes
+	if encoding == EncodingUTF16LE || encoding == EncodingUTF16BE {
+		reader = bufio.NewReader(&utf16Reader{reader: reader, bigEndian: encoding == EncodingUTF16BE})
+		encoding = EncodingUTF8
+	}
+	return &lineReader{reader: reader, options: options, encoding: encoding}
+}
+
+// skipByteOrderMark skips the byte order mark at the start of the input, if any,
+// and returns the encoding of the input
+func skipByteOrderMark(reader *bufio.Reader, encoding string) string {
+	if encoding == "" {
+		encoding = EncodingAuto
+	}
+	prefix, _ := reader.Peek(3)
+	for _, bom := range byteOrderMarks {
+		if !bytes.HasPrefix(prefix, bom.mark) || (encoding != EncodingAuto && encoding != bom.encoding) {
+			continue
+		}
+		reader.Discard(len(bom.mark))
+		return bom.encoding
+	}
+	return encoding
+}
+
+// next returns the next line, or io.EOF once all lines are read
+func (r *lineReader) next() (string, error) {
+	line := []byte{}
+	truncated := false
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		if err != nil && err != bufio.ErrBufferFull && err != io.EOF {
+			return "", &LineError{Line: r.line + 1, Err: err}
+		}
+		if err == io.EOF && len(chunk) == 0 && len(line) == 0 && !truncated {
+			return "", io.EOF
+		}
+
+		if room := r.options.MaxLineSize - int64(len(line)); int64(len(bytes.TrimRight(chunk, "\r\n"))) > room {
+			if r.options.LongLines != LongLinesTruncate {
+				return "", &LineError{Line: r.line + 1, Err: fmt.Errorf("%w, longer than %d bytes", ErrLineTooLong, r.options.MaxLineSize)}
+			}
+			line = append(line, chunk[:int(room)]...)
+			truncated = true
+		} else if !truncated {
+			line = append(line, chunk...)
+		}
+
+		if err != bufio.ErrBufferFull {
+			break
+		}
+	}
+
+	r.line++
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if truncated {
+		line = trimPartialRune(line, r.encoding)
+	}
+	return r.decode(line), nil
+}
+
+// decode converts a line into UTF-8, escaping bytes that are not valid UTF-8 like \xff
+func (r *lineReader) decode(line []byte) string {
+	if r.encoding == EncodingLatin1 {
+		runes := make([]rune, len(line))
+		for i, b := range line {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+
+	if utf8.Valid(line) {
+		return string(line)
+	}
+	var builder strings.Builder
+	for len(line) > 0 {
+		char, size := utf8.DecodeRune(line)
+		if char == utf8.RuneError && size == 1 {
+			fmt.Fprintf(&builder, `\x%02x`, line[0])
+		} else {
+			builder.Write(line[:size])
+		}
+		line = line[size:]
+	}
+	return builder.String()
+}
+
+// trimPartialRune removes an incomplete UTF-8 character left at the end of a
+// truncated line
+func trimPartialRune(line []byte, encoding string) []byte {
+	if encoding == EncodingLatin1 {
+		return line
+	}
+	for i := 1; i < utf8.UTFMax && i <= len(line); i++ {
+		if utf8.RuneStart(line[len(line)-i]) {
+			if !utf8.FullRune(line[len(line)-i:]) {
+				return line[:len(line)-i]
+			}
+			break
+		}
+	}
+	return line
+}
+
+// utf16Reader converts UTF-16 input into UTF-8
+type utf16Reader struct {
+	reader    *bufio.Reader
+	bigEndian bool
+	pending   []byte // Converted bytes not read yet
+}
+
+func (r *utf16Reader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		unit, err := r.unit()
+		if err != nil {
+			return 0, err
+		}
+
+		char := rune(unit)
+		if char >= 0xD800 && char < 0xDC00 {
+			// A surrogate pair encodes a character outside the basic plane
+			next, err := r.unit()
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+			char = utf16.DecodeRune(char, rune(next))
+		}
+		r.pending = utf8.AppendRune(r.pending, char) // Lone surrogates are written as the replacement character
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// unit reads the next UTF-16 code unit. A lone byte at the end of the input is
+// read as the replacement character.
+func (r *utf16Reader) unit() (uint16, error) {
+	var pair [2]byte
+	n, err := io.ReadFull(r.reader, pair[:])
+	if err == io.ErrUnexpectedEOF {
+		return utf8.RuneError, nil
+	}
+	if n < 2 {
+		return 0, err
+	}
+	if r.bigEndian {
+		return uint16(pair[0])<<8 | uint16(pair[1]), nil
+	}
+	return uint16(pair[1])<<8 | uint16(pair[0]), nil
+}
@@ -0,0 +1,136 @@
+package fileutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileUtil_EachLine_Options(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		options ReadOptions
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "crlf line breaks",
+			content: "first\r\nsecond\r\nlast\r",
+			want:    []string{"first", "second", "last"},
+		},
+		{
+			name:    "utf-8 byte order mark",
+			content: "\xEF\xBB\xBFfirst\nsecond",
+			want:    []string{"first", "second"},
+		},
+		{
+			name:    "invalid utf-8 escaped",
+			content: "caf\xE9 \xFF\nok",
+			want:    []string{`caf\xe9 \xff`, "ok"},
+		},
+		{
+			name:    "latin1",
+			content: "caf\xE9\n",
+			options: ReadOptions{Encoding: EncodingLatin1},
+			want:    []string{"café"},
+		},
+		{
+			name:    "utf-16le with byte order mark",
+			content: "\xFF\xFEh\x00\xE9\x00\r\x00\n\x00=\xD8\x00\xDE\n\x00",
+			want:    []string{"hé", "😀"},
+		},
+		{
+			name:    "utf-16be without byte order mark",
+			content: "\x00h\x00i\x00\n\x00!",
+			options: ReadOptions{Encoding: EncodingUTF16BE},
+			want:    []string{"hi", "!"},
+		},
+		{
+			name:    "line longer than the default scanner buffer",
+			content: strings.Repeat("x", 100000) + "\nshort",
+			want:    []string{strings.Repeat("x", 100000), "short"},
+		},
+		{
+			name:    "long line error",
+			content: "short\n" + strings.Repeat("x", 100) + "\nshort",
+			options: ReadOptions{MaxLineSize: 10},
+			want:    []string{"short"},
+			wantErr: "error reading file: line 2: line too long, longer than 10 bytes",
+		},
+		{
+			name:    "long line truncated",
+			content: strings.Repeat("x", 100) + "\r\nshort\n",
+			options: ReadOptions{MaxLineSize: 10, LongLines: LongLinesTruncate},
+			want:    []string{strings.Repeat("x", 10), "short"},
+		},
+		{
+			name:    "truncated within a character",
+			content: "aaaaaaaaa€\n",
+			options: ReadOptions{MaxLineSize: 10, LongLines: LongLinesTruncate},
+			want:    []string{"aaaaaaaaa"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lines.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			fileUtil := &FileUtil{Options: tt.options}
+			lines := []string{}
+			err := fileUtil.EachLine(path, func(line string) error {
+				lines = append(lines, line)
+				return nil
+			})
+
+			if tt.wantErr != "" {
+				var lineErr *LineError
+				if err == nil || err.Error() != tt.wantErr || !errors.As(err, &lineErr) || !errors.Is(err, ErrLineTooLong) {
+					t.Errorf("FileUtil.EachLine() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("FileUtil.EachLine() error = %v", err)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("FileUtil.EachLine() read %q, want %q", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileUtil_ReadLines_Error(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	if err := os.WriteFile(path, []byte("short\n"+strings.Repeat("x", 100)+"\nshort\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// A failing line is reported instead of returning the lines before it as the whole file
+	fileUtil := &FileUtil{Options: ReadOptions{MaxLineSize: 10}}
+	lines, err := fileUtil.ReadLines(path)
+	if !errors.Is(err, ErrLineTooLong) || lines != nil {
+		t.Errorf("FileUtil.ReadLines() = %q, %v, want %v", lines, err, ErrLineTooLong)
+	}
+}
+
+func TestFileUtil_SplitChunks_UTF16(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	content := "\xFF\xFE" + strings.Repeat("a\x00\n\x00", 100)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// Line breaks can't be found in UTF-16 without decoding it
+	chunks, err := NewFileUtil().SplitChunks(path, 4)
+	if err != nil {
+		t.Fatalf("FileUtil.SplitChunks() error = %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Errorf("FileUtil.SplitChunks() = %v, want a single chunk", chunks)
+	}
+}
@@ -30,11 +30,11 @@ type SimpleLogger struct {
 	log   *log.Logger
 }
 
-// NewLogger creates a new logger
+// NewLogger creates a new logger writing to stderr, keeping stdout for output
 func NewLogger() *SimpleLogger {
 	return &SimpleLogger{
 		level: INFO,
-		log:   log.New(os.Stdout, "", log.LstdFlags),
+		log:   log.New(os.Stderr, "", log.LstdFlags),
 	}
 }
 
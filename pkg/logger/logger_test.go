@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_WritesToStderr(t *testing.T) {
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	defer func() { os.Stdout, os.Stderr = oldStdout, oldStderr }()
+
+	l := NewLogger()
+	l.Info("skipped %d lines", 3)
+	l.Warn("dropped %d patterns", 2)
+
+	out, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Errorf("stdout = %q, want nothing", out)
+	}
+
+	diagnostics, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"[INFO] skipped 3 lines", "[WARN] dropped 2 patterns"} {
+		if !strings.Contains(string(diagnostics), want) {
+			t.Errorf("stderr = %q, want %q", diagnostics, want)
+		}
+	}
+}